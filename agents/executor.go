@@ -2,6 +2,8 @@ package agents
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -12,7 +14,8 @@ import (
 // Agent is the interface for the planning component that decides what to do next.
 type Agent interface {
 	// Plan takes the intermediate steps so far and returns the next action(s) or finish.
-	Plan(ctx context.Context, intermediateSteps []AgentStep, inputs map[string]any) (*AgentOutput, error)
+	// Options, such as callbacks, are passed on to the model call.
+	Plan(ctx context.Context, intermediateSteps []AgentStep, inputs map[string]any, opts ...core.Option) (*AgentOutput, error)
 
 	// InputKeys returns the expected input keys.
 	InputKeys() []string
@@ -21,6 +24,10 @@ type Agent interface {
 	OutputKeys() []string
 }
 
+// ErrRepeatedAction is returned by AgentExecutor when the agent exceeds the
+// limit set by WithRepeatActionLimit and WithStopOnRepeatedAction is enabled.
+var ErrRepeatedAction = errors.New("agent repeated the same action")
+
 // AgentExecutor runs an agent loop: plan -> execute tool -> plan -> ... -> finish.
 // It implements Runnable[map[string]any, map[string]any].
 type AgentExecutor struct {
@@ -30,6 +37,8 @@ type AgentExecutor struct {
 	maxIterations           int
 	returnIntermediateSteps bool
 	handleParsingErrors     bool
+	repeatActionLimit       int
+	stopOnRepeatedAction    bool
 	name                    string
 	callbacks               []core.CallbackHandler
 }
@@ -73,6 +82,22 @@ func WithHandleParsingErrors(v bool) ExecutorOption {
 	return func(e *AgentExecutor) { e.handleParsingErrors = v }
 }
 
+// WithRepeatActionLimit guards against an agent calling the same tool with
+// the same input over and over. Once an action has run n times in a row,
+// further identical actions are not executed; the agent instead receives an
+// observation telling it to try something different. Inputs are compared
+// after normalizing JSON. Zero, the default, disables the guard.
+func WithRepeatActionLimit(n int) ExecutorOption {
+	return func(e *AgentExecutor) { e.repeatActionLimit = n }
+}
+
+// WithStopOnRepeatedAction makes the executor fail with ErrRepeatedAction,
+// instead of sending a corrective observation, when the limit set by
+// WithRepeatActionLimit is exceeded.
+func WithStopOnRepeatedAction(v bool) ExecutorOption {
+	return func(e *AgentExecutor) { e.stopOnRepeatedAction = v }
+}
+
 // GetName returns the executor name.
 func (e *AgentExecutor) GetName() string {
 	if e.name != "" {
@@ -87,11 +112,18 @@ func (e *AgentExecutor) Invoke(ctx context.Context, input map[string]any, opts .
 
 	// Notify callbacks.
 	for _, cb := range cfg.Callbacks {
-		cb.OnChainStart(ctx, input, cfg.RunID, "", map[string]any{"name": e.GetName()})
+		cb.OnChainStart(ctx, input, cfg.RunID, cfg.ParentRunID, map[string]any{"name": e.GetName()})
 	}
 
 	var intermediateSteps []AgentStep
 	iterations := 0
+	// childRuns numbers planning steps and tool invocations so each gets a
+	// run ID derived from the executor's own.
+	childRuns := 0
+
+	// Track consecutive identical actions for WithRepeatActionLimit.
+	var lastAction, repeatObservation string
+	repeats := 0
 
 	for iterations < e.maxIterations {
 		select {
@@ -100,13 +132,16 @@ func (e *AgentExecutor) Invoke(ctx context.Context, input map[string]any, opts .
 		default:
 		}
 
-		output, err := e.agent.Plan(ctx, intermediateSteps, input)
+		output, err := e.agent.Plan(ctx, intermediateSteps, input, core.ChildOptions(cfg, childRuns, opts...)...)
+		childRuns++
 		if err != nil {
-			if e.handleParsingErrors {
+			// A model without tool calling won't recover on retry.
+			if e.handleParsingErrors && !errors.Is(err, ErrToolCallingUnsupported) {
 				intermediateSteps = append(intermediateSteps, AgentStep{
 					Action:      AgentAction{Tool: "_error", ToolInput: "", Log: err.Error()},
 					Observation: fmt.Sprintf("Error: %v. Please try again with valid output.", err),
 				})
+				lastAction = ""
 				iterations++
 				continue
 			}
@@ -142,6 +177,44 @@ func (e *AgentExecutor) Invoke(ctx context.Context, input map[string]any, opts .
 				}, cfg.RunID)
 			}
 
+			if e.repeatActionLimit > 0 {
+				key := actionKey(action)
+				if key == lastAction {
+					repeats++
+				} else {
+					lastAction, repeats = key, 1
+				}
+				if repeats > e.repeatActionLimit {
+					if e.stopOnRepeatedAction {
+						err := fmt.Errorf("%w: %s called with the same input %d times in a row",
+							ErrRepeatedAction, action.Tool, repeats)
+						for _, cb := range cfg.Callbacks {
+							cb.OnChainError(ctx, err, cfg.RunID)
+						}
+						return nil, err
+					}
+					// The previous step is the last real run of this action.
+					if repeats == e.repeatActionLimit+1 {
+						repeatObservation = intermediateSteps[len(intermediateSteps)-1].Observation
+					}
+					intermediateSteps = append(intermediateSteps, AgentStep{
+						Action: action,
+						Observation: fmt.Sprintf("You already called %s with this input %d times in a row and got: %s\nDo not repeat it. Try a different tool or input, or give your final answer.",
+							action.Tool, e.repeatActionLimit, repeatObservation),
+					})
+					continue
+				}
+			}
+
+			if action.ArgsError != "" {
+				intermediateSteps = append(intermediateSteps, AgentStep{
+					Action: action,
+					Observation: fmt.Sprintf("Error: the arguments for tool %s were not valid JSON (%s). Call it again with arguments that are a valid JSON object.",
+						action.Tool, action.ArgsError),
+				})
+				continue
+			}
+
 			tool, ok := e.toolMap[action.Tool]
 			if !ok {
 				observation := fmt.Sprintf("Tool %q not found. Available tools: %s",
@@ -153,25 +226,28 @@ func (e *AgentExecutor) Invoke(ctx context.Context, input map[string]any, opts .
 				continue
 			}
 
+			toolRunID := core.ChildRunID(cfg.RunID, childRuns)
+			childRuns++
 			for _, cb := range cfg.Callbacks {
-				cb.OnToolStart(ctx, action.Tool, action.ToolInput, cfg.RunID, "")
+				cb.OnToolStart(ctx, action.Tool, action.ToolInput, toolRunID, cfg.RunID)
 			}
 
-			observation, err := tool.Run(ctx, action.ToolInput)
+			observation, artifact, err := tools.RunTool(ctx, tool, action.ToolInput)
 			if err != nil {
 				observation = fmt.Sprintf("Error executing tool %s: %v", action.Tool, err)
 				for _, cb := range cfg.Callbacks {
-					cb.OnToolError(ctx, err, cfg.RunID)
+					cb.OnToolError(ctx, err, toolRunID)
 				}
 			} else {
 				for _, cb := range cfg.Callbacks {
-					cb.OnToolEnd(ctx, observation, cfg.RunID)
+					cb.OnToolEnd(ctx, observation, toolRunID)
 				}
 			}
 
 			intermediateSteps = append(intermediateSteps, AgentStep{
 				Action:      action,
 				Observation: observation,
+				Artifact:    artifact,
 			})
 		}
 
@@ -201,6 +277,9 @@ func (e *AgentExecutor) Stream(ctx context.Context, input map[string]any, opts .
 func (e *AgentExecutor) Batch(ctx context.Context, inputs []map[string]any, opts ...core.Option) ([]map[string]any, error) {
 	results := make([]map[string]any, len(inputs))
 	for i, input := range inputs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		result, err := e.Invoke(ctx, input, opts...)
 		if err != nil {
 			return nil, fmt.Errorf("batch item %d: %w", i, err)
@@ -210,6 +289,19 @@ func (e *AgentExecutor) Batch(ctx context.Context, inputs []map[string]any, opts
 	return results, nil
 }
 
+// actionKey identifies an action for repeat detection. JSON inputs are
+// re-encoded so that formatting and key order do not matter.
+func actionKey(action AgentAction) string {
+	input := strings.TrimSpace(action.ToolInput)
+	var v any
+	if err := json.Unmarshal([]byte(input), &v); err == nil {
+		if normalized, err := json.Marshal(v); err == nil {
+			input = string(normalized)
+		}
+	}
+	return action.Tool + "\x00" + input
+}
+
 func (e *AgentExecutor) availableToolNames() string {
 	names := make([]string, len(e.tools))
 	for i, t := range e.tools {
@@ -64,7 +64,7 @@ func NewReActAgent(llm llms.ChatModel, agentTools []tools.Tool, prompt *prompts.
 }
 
 // Plan decides the next action based on intermediate steps and inputs.
-func (a *ReActAgent) Plan(ctx context.Context, intermediateSteps []AgentStep, inputs map[string]any) (*AgentOutput, error) {
+func (a *ReActAgent) Plan(ctx context.Context, intermediateSteps []AgentStep, inputs map[string]any, opts ...core.Option) (*AgentOutput, error) {
 	// Build tool descriptions and names.
 	toolDescs := a.renderToolDescriptions()
 	toolNames := a.renderToolNames()
@@ -88,7 +88,7 @@ func (a *ReActAgent) Plan(ctx context.Context, intermediateSteps []AgentStep, in
 	}
 
 	// Call the model with stop sequences.
-	response, err := a.llm.Invoke(ctx, messages, core.WithStop("\nObservation:"))
+	response, err := a.llm.Invoke(ctx, messages, append(opts[:len(opts):len(opts)], core.WithStop("\nObservation:"))...)
 	if err != nil {
 		return nil, fmt.Errorf("LLM call failed: %w", err)
 	}
@@ -3,7 +3,9 @@ package agents
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/LucaLanziani/langchain-go/core"
 	"github.com/LucaLanziani/langchain-go/llms"
@@ -11,30 +13,74 @@ import (
 	"github.com/LucaLanziani/langchain-go/tools"
 )
 
+// FinalAnswerToolName is the name of the tool a ToolCallingAgent configured
+// with WithResponseFormat must call to finish.
+const FinalAnswerToolName = "final_answer"
+
+// ErrToolCallingUnsupported is returned by ToolCallingAgent.Plan when the
+// model reports, via llms.Capabilities, that it cannot make tool calls.
+var ErrToolCallingUnsupported = errors.New("model does not support tool calling")
+
 // ToolCallingAgent uses a chat model's native tool calling capability.
 // This is the modern, recommended agent type.
 type ToolCallingAgent struct {
-	llm    llms.ChatModel
-	prompt *prompts.ChatPromptTemplate
-	tools  []tools.Tool
+	llm            llms.ChatModel
+	prompt         *prompts.ChatPromptTemplate
+	tools          []tools.Tool
+	responseFormat map[string]any
+	err            error
+}
+
+// ToolCallingAgentOption configures a ToolCallingAgent.
+type ToolCallingAgentOption func(*ToolCallingAgent)
+
+// WithResponseFormat makes the agent return structured output. A
+// final_answer tool taking the given JSON Schema as its parameters is bound
+// alongside the agent's tools, and the agent finishes only when the model
+// calls it. The call's arguments become the executor's result map.
+func WithResponseFormat(schema map[string]any) ToolCallingAgentOption {
+	return func(a *ToolCallingAgent) { a.responseFormat = schema }
 }
 
 // NewToolCallingAgent creates a new ToolCallingAgent.
 // The prompt must include a MessagesPlaceholder("agent_scratchpad") for intermediate steps.
-func NewToolCallingAgent(llm llms.ChatModel, agentTools []tools.Tool, prompt *prompts.ChatPromptTemplate) *ToolCallingAgent {
-	// Bind tools to the model.
-	toolDefs := tools.ToDefinitions(agentTools...)
-	boundLLM := llm.BindTools(toolDefs...)
-
-	return &ToolCallingAgent{
-		llm:    boundLLM,
+func NewToolCallingAgent(llm llms.ChatModel, agentTools []tools.Tool, prompt *prompts.ChatPromptTemplate, opts ...ToolCallingAgentOption) *ToolCallingAgent {
+	a := &ToolCallingAgent{
 		prompt: prompt,
 		tools:  agentTools,
 	}
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	// Models that don't report capabilities are assumed to support tools.
+	if caps, ok := llms.GetCapabilities(llm); ok && !caps.ToolCalling {
+		a.err = fmt.Errorf("%w: %s; use a ReActAgent for models without native tool calling",
+			ErrToolCallingUnsupported, llm.GetName())
+	}
+
+	// Bind tools to the model.
+	toolDefs := tools.ToDefinitions(agentTools...)
+	if a.responseFormat != nil {
+		toolDefs = append(toolDefs, llms.ToolDefinition{
+			Name:        FinalAnswerToolName,
+			Description: "Respond to the user with the final answer. Call this once you have all the information you need.",
+			Parameters:  a.responseFormat,
+		})
+	}
+	a.llm = llm.BindTools(toolDefs...)
+
+	return a
 }
 
 // Plan decides the next action(s) based on intermediate steps and inputs.
-func (a *ToolCallingAgent) Plan(ctx context.Context, intermediateSteps []AgentStep, inputs map[string]any) (*AgentOutput, error) {
+// It fails immediately with ErrToolCallingUnsupported if the model reports
+// that it cannot make tool calls.
+func (a *ToolCallingAgent) Plan(ctx context.Context, intermediateSteps []AgentStep, inputs map[string]any, opts ...core.Option) (*AgentOutput, error) {
+	if a.err != nil {
+		return nil, a.err
+	}
+
 	// Build the agent scratchpad from intermediate steps.
 	scratchpad := formatToolCallingSteps(intermediateSteps)
 
@@ -52,22 +98,27 @@ func (a *ToolCallingAgent) Plan(ctx context.Context, intermediateSteps []AgentSt
 	}
 
 	// Call the model.
-	response, err := a.llm.Invoke(ctx, messages)
+	response, err := a.llm.Invoke(ctx, messages, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("LLM call failed: %w", err)
 	}
 
+	if a.responseFormat != nil {
+		return a.planStructured(response)
+	}
+
 	// If the model returned tool calls, create actions.
-	if len(response.ToolCalls) > 0 {
-		actions := make([]AgentAction, len(response.ToolCalls))
-		for i, tc := range response.ToolCalls {
-			actions[i] = AgentAction{
+	if len(response.ToolCalls) > 0 || len(response.InvalidToolCalls) > 0 {
+		actions := make([]AgentAction, 0, len(response.ToolCalls)+len(response.InvalidToolCalls))
+		for _, tc := range response.ToolCalls {
+			actions = append(actions, AgentAction{
 				Tool:       tc.Name,
 				ToolInput:  string(tc.Args),
 				Log:        fmt.Sprintf("Calling tool: %s", tc.Name),
 				MessageLog: []core.Message{response},
-			}
+			})
 		}
+		actions = append(actions, invalidToolCallActions(response)...)
 		return &AgentOutput{Actions: actions}, nil
 	}
 
@@ -83,6 +134,59 @@ func (a *ToolCallingAgent) Plan(ctx context.Context, intermediateSteps []AgentSt
 	}, nil
 }
 
+// planStructured handles a model response when a response format is set. A
+// final_answer call finishes the run, taking precedence over any other calls
+// in the same turn; a plain-text reply is an error so that executors with
+// WithHandleParsingErrors can ask the model to try again.
+func (a *ToolCallingAgent) planStructured(response *core.AIMessage) (*AgentOutput, error) {
+	var actions []AgentAction
+	for _, tc := range response.ToolCalls {
+		if tc.Name == FinalAnswerToolName {
+			var values map[string]any
+			if err := json.Unmarshal(tc.Args, &values); err != nil {
+				return nil, fmt.Errorf("failed to parse %s arguments: %w", FinalAnswerToolName, err)
+			}
+			if values == nil {
+				values = make(map[string]any)
+			}
+			return &AgentOutput{
+				Finish: &AgentFinish{
+					ReturnValues: values,
+					Log:          string(tc.Args),
+					MessageLog:   []core.Message{response},
+				},
+			}, nil
+		}
+		actions = append(actions, AgentAction{
+			Tool:       tc.Name,
+			ToolInput:  string(tc.Args),
+			Log:        fmt.Sprintf("Calling tool: %s", tc.Name),
+			MessageLog: []core.Message{response},
+		})
+	}
+	actions = append(actions, invalidToolCallActions(response)...)
+	if len(actions) == 0 {
+		return nil, fmt.Errorf("model responded without calling the %s tool", FinalAnswerToolName)
+	}
+	return &AgentOutput{Actions: actions}, nil
+}
+
+// invalidToolCallActions turns the response's invalid tool calls into
+// actions carrying the parse error, so the executor can report it back.
+func invalidToolCallActions(response *core.AIMessage) []AgentAction {
+	actions := make([]AgentAction, len(response.InvalidToolCalls))
+	for i, tc := range response.InvalidToolCalls {
+		actions[i] = AgentAction{
+			Tool:       tc.Name,
+			ToolInput:  tc.Args,
+			Log:        fmt.Sprintf("Invalid arguments for tool %s: %s", tc.Name, tc.Error),
+			ArgsError:  tc.Error,
+			MessageLog: []core.Message{response},
+		}
+	}
+	return actions
+}
+
 // InputKeys returns the expected input keys.
 func (a *ToolCallingAgent) InputKeys() []string {
 	// Filter out agent_scratchpad from prompt variables.
@@ -95,9 +199,19 @@ func (a *ToolCallingAgent) InputKeys() []string {
 	return keys
 }
 
-// OutputKeys returns the output keys.
+// OutputKeys returns the output keys. With a response format these are the
+// top-level properties of its schema.
 func (a *ToolCallingAgent) OutputKeys() []string {
-	return []string{"output"}
+	if a.responseFormat == nil {
+		return []string{"output"}
+	}
+	props, _ := a.responseFormat["properties"].(map[string]any)
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 // formatToolCallingSteps converts intermediate steps to messages for the scratchpad.
@@ -0,0 +1,269 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/LucaLanziani/langchain-go/core"
+	"github.com/LucaLanziani/langchain-go/llms"
+	"github.com/LucaLanziani/langchain-go/prompts"
+	"github.com/LucaLanziani/langchain-go/tools"
+)
+
+// scriptedChatModel returns the given responses in order and records the
+// tools bound to it and the config of each call.
+type scriptedChatModel struct {
+	responses []*core.AIMessage
+	calls     int
+	bound     []llms.ToolDefinition
+	configs   []*core.RunnableConfig
+}
+
+func (m *scriptedChatModel) GetName() string { return "scripted" }
+func (m *scriptedChatModel) Invoke(_ context.Context, _ []core.Message, opts ...core.Option) (*core.AIMessage, error) {
+	m.configs = append(m.configs, core.ApplyOptions(opts...))
+	msg := m.responses[m.calls%len(m.responses)]
+	m.calls++
+	return msg, nil
+}
+func (m *scriptedChatModel) Stream(ctx context.Context, input []core.Message, opts ...core.Option) (*core.StreamIterator[*core.AIMessage], error) {
+	msg, err := m.Invoke(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan core.StreamChunk[*core.AIMessage], 1)
+	ch <- core.StreamChunk[*core.AIMessage]{Value: msg}
+	close(ch)
+	return core.NewStreamIterator(ch), nil
+}
+func (m *scriptedChatModel) Batch(ctx context.Context, inputs [][]core.Message, opts ...core.Option) ([]*core.AIMessage, error) {
+	results := make([]*core.AIMessage, len(inputs))
+	for i, in := range inputs {
+		results[i], _ = m.Invoke(ctx, in, opts...)
+	}
+	return results, nil
+}
+func (m *scriptedChatModel) Generate(ctx context.Context, input []core.Message, opts ...core.Option) (*llms.ChatResult, error) {
+	msg, err := m.Invoke(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &llms.ChatResult{Generations: []*llms.ChatGeneration{{Message: msg}}}, nil
+}
+func (m *scriptedChatModel) BindTools(defs ...llms.ToolDefinition) llms.ChatModel {
+	m.bound = defs
+	return m
+}
+func (m *scriptedChatModel) WithStructuredOutput(map[string]any) llms.ChatModel { return m }
+
+func toolCallMessage(name, args string) *core.AIMessage {
+	return core.NewAIMessageWithToolCalls("", []core.ToolCall{
+		{ID: "call_" + name, Name: name, Args: json.RawMessage(args), Type: "function"},
+	})
+}
+
+func testAgentPrompt() *prompts.ChatPromptTemplate {
+	return prompts.NewChatPromptTemplate(
+		prompts.System("You are a helpful assistant."),
+		prompts.Placeholder("agent_scratchpad"),
+		prompts.Human("{input}"),
+	)
+}
+
+func TestToolCallingAgentResponseFormat(t *testing.T) {
+	lookup := tools.NewTool("lookup", "Look up a city", func(_ context.Context, input string) (string, error) {
+		return "Paris has 2.1 million inhabitants.", nil
+	})
+	model := &scriptedChatModel{responses: []*core.AIMessage{
+		toolCallMessage("lookup", `{"input":"Paris"}`),
+		toolCallMessage(FinalAnswerToolName, `{"city":"Paris","population":2100000}`),
+	}}
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"city":       map[string]any{"type": "string"},
+			"population": map[string]any{"type": "integer"},
+		},
+		"required": []string{"city", "population"},
+	}
+
+	agent := NewToolCallingAgent(model, []tools.Tool{lookup}, testAgentPrompt(), WithResponseFormat(schema))
+	if len(model.bound) != 2 || model.bound[1].Name != FinalAnswerToolName {
+		t.Fatalf("expected lookup and final_answer to be bound, got %+v", model.bound)
+	}
+	if keys := agent.OutputKeys(); len(keys) != 2 || keys[0] != "city" || keys[1] != "population" {
+		t.Errorf("expected output keys [city population], got %v", keys)
+	}
+
+	executor := NewAgentExecutor(agent, []tools.Tool{lookup})
+	result, err := executor.Invoke(context.Background(), map[string]any{"input": "How big is Paris?"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result["city"] != "Paris" || result["population"] != float64(2100000) {
+		t.Errorf("unexpected result: %v", result)
+	}
+}
+
+func TestToolCallingAgentResponseFormatRequiresFinalAnswer(t *testing.T) {
+	model := &scriptedChatModel{responses: []*core.AIMessage{
+		core.NewAIMessage("Paris is big."),
+		toolCallMessage(FinalAnswerToolName, `{"city":"Paris"}`),
+	}}
+	schema := map[string]any{"type": "object", "properties": map[string]any{"city": map[string]any{"type": "string"}}}
+	agent := NewToolCallingAgent(model, nil, testAgentPrompt(), WithResponseFormat(schema))
+
+	// Without parsing-error handling a plain-text reply fails the run.
+	_, err := NewAgentExecutor(agent, nil).Invoke(context.Background(), map[string]any{"input": "?"})
+	if err == nil {
+		t.Fatal("expected an error for a reply without final_answer")
+	}
+
+	// With it, the model is asked again and the structured answer is returned.
+	model.calls = 0
+	result, err := NewAgentExecutor(agent, nil, WithHandleParsingErrors(true)).
+		Invoke(context.Background(), map[string]any{"input": "?"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result["city"] != "Paris" {
+		t.Errorf("unexpected result: %v", result)
+	}
+}
+
+// textOnlyChatModel is a scriptedChatModel that reports no tool calling.
+type textOnlyChatModel struct {
+	scriptedChatModel
+}
+
+func (m *textOnlyChatModel) Capabilities() llms.Capabilities {
+	return llms.Capabilities{Streaming: true}
+}
+
+func TestToolCallingAgentRequiresToolCalling(t *testing.T) {
+	model := &textOnlyChatModel{scriptedChatModel{responses: []*core.AIMessage{core.NewAIMessage("hi")}}}
+	agent := NewToolCallingAgent(model, nil, testAgentPrompt())
+
+	// Parsing-error handling must not retry a model that can never call tools.
+	_, err := NewAgentExecutor(agent, nil, WithHandleParsingErrors(true)).
+		Invoke(context.Background(), map[string]any{"input": "?"})
+	if !errors.Is(err, ErrToolCallingUnsupported) {
+		t.Fatalf("expected ErrToolCallingUnsupported, got %v", err)
+	}
+	if model.calls != 0 {
+		t.Errorf("expected the model not to be called, got %d calls", model.calls)
+	}
+}
+
+func TestToolCallingAgentInvalidToolCallArgs(t *testing.T) {
+	invalid := core.NewAIMessage("")
+	invalid.InvalidToolCalls = []core.InvalidToolCall{
+		{ID: "call_1", Name: "lookup", Args: `{"q":`, Error: "unexpected end of JSON input"},
+	}
+	model := &scriptedChatModel{responses: []*core.AIMessage{invalid, core.NewAIMessage("done")}}
+
+	ran := false
+	lookup := tools.NewTool("lookup", "Look something up", func(context.Context, string) (string, error) {
+		ran = true
+		return "", nil
+	})
+	agent := NewToolCallingAgent(model, []tools.Tool{lookup}, testAgentPrompt())
+	result, err := NewAgentExecutor(agent, []tools.Tool{lookup}, WithReturnIntermediateSteps(true)).
+		Invoke(context.Background(), map[string]any{"input": "?"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ran {
+		t.Error("expected the tool not to run with invalid arguments")
+	}
+
+	steps := result["intermediate_steps"].([]AgentStep)
+	if len(steps) != 1 {
+		t.Fatalf("expected 1 step, got %d", len(steps))
+	}
+	if !strings.Contains(steps[0].Observation, "not valid JSON") || !strings.Contains(steps[0].Observation, "unexpected end of JSON input") {
+		t.Errorf("expected an invalid-arguments observation, got %q", steps[0].Observation)
+	}
+	if result["output"] != "done" {
+		t.Errorf("expected the model to finish after the retry, got %v", result["output"])
+	}
+}
+
+func TestExecutorRepeatActionLimit(t *testing.T) {
+	newModel := func() *scriptedChatModel {
+		return &scriptedChatModel{responses: []*core.AIMessage{
+			toolCallMessage("lookup", `{"q":"x","n":1}`),
+			toolCallMessage("lookup", `{ "n": 1, "q": "x" }`),
+			toolCallMessage("lookup", `{"q":"x","n":1}`),
+			core.NewAIMessage("done"),
+		}}
+	}
+	runs := 0
+	lookup := tools.NewTool("lookup", "Look something up", func(context.Context, string) (string, error) {
+		runs++
+		return "no results", nil
+	})
+
+	agent := NewToolCallingAgent(newModel(), []tools.Tool{lookup}, testAgentPrompt())
+	result, err := NewAgentExecutor(agent, []tools.Tool{lookup},
+		WithRepeatActionLimit(2), WithReturnIntermediateSteps(true)).
+		Invoke(context.Background(), map[string]any{"input": "?"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if runs != 2 {
+		t.Errorf("expected the tool to run twice, ran %d times", runs)
+	}
+	steps := result["intermediate_steps"].([]AgentStep)
+	if len(steps) != 3 || !strings.Contains(steps[2].Observation, "already called lookup") ||
+		!strings.Contains(steps[2].Observation, "no results") {
+		t.Errorf("expected a corrective third observation, got %+v", steps)
+	}
+
+	// With WithStopOnRepeatedAction the run fails instead.
+	runs = 0
+	agent = NewToolCallingAgent(newModel(), []tools.Tool{lookup}, testAgentPrompt())
+	_, err = NewAgentExecutor(agent, []tools.Tool{lookup},
+		WithRepeatActionLimit(2), WithStopOnRepeatedAction(true)).
+		Invoke(context.Background(), map[string]any{"input": "?"})
+	if !errors.Is(err, ErrRepeatedAction) {
+		t.Fatalf("expected ErrRepeatedAction, got %v", err)
+	}
+	if runs != 2 {
+		t.Errorf("expected the tool to run twice, ran %d times", runs)
+	}
+}
+
+func TestExecutorPassesOptionsToModel(t *testing.T) {
+	model := &scriptedChatModel{responses: []*core.AIMessage{
+		toolCallMessage("lookup", `{}`),
+		core.NewAIMessage("done"),
+	}}
+	lookup := tools.NewTool("lookup", "Look something up", func(context.Context, string) (string, error) {
+		return "ok", nil
+	})
+	handler := &core.BaseCallbackHandler{}
+	agent := NewToolCallingAgent(model, []tools.Tool{lookup}, testAgentPrompt())
+	_, err := NewAgentExecutor(agent, []tools.Tool{lookup}).Invoke(context.Background(),
+		map[string]any{"input": "?"}, core.WithRunID("run-1"), core.WithCallbacks(handler))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(model.configs) != 2 {
+		t.Fatalf("expected 2 model calls, got %d", len(model.configs))
+	}
+	// Planning steps and the tool run get distinct child run IDs.
+	want := []string{core.ChildRunID("run-1", 0), core.ChildRunID("run-1", 2)}
+	for i, cfg := range model.configs {
+		if len(cfg.Callbacks) != 1 || cfg.Callbacks[0] != handler {
+			t.Errorf("call %d: expected the executor's callbacks, got %v", i, cfg.Callbacks)
+		}
+		if cfg.ParentRunID != "run-1" || cfg.RunID != want[i] {
+			t.Errorf("call %d: expected run %s under run-1, got %s under %s", i, want[i], cfg.RunID, cfg.ParentRunID)
+		}
+	}
+}
@@ -17,6 +17,11 @@ type AgentAction struct {
 	// Log is additional information about why this action was taken.
 	Log string `json:"log"`
 
+	// ArgsError is set when the model's arguments for Tool were not valid
+	// JSON. The executor reports it back to the model as the observation
+	// instead of running the tool.
+	ArgsError string `json:"args_error,omitempty"`
+
 	// MessageLog contains the messages that led to this action.
 	MessageLog []core.Message `json:"-"`
 }
@@ -41,6 +46,10 @@ type AgentStep struct {
 
 	// Observation is the result of executing the action.
 	Observation string `json:"observation"`
+
+	// Artifact is the structured output of the tool, if it is a
+	// tools.ArtifactTool.
+	Artifact any `json:"-"`
 }
 
 // AgentOutput is the union type returned by an agent's planning step.
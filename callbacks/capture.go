@@ -0,0 +1,123 @@
+package callbacks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/LucaLanziani/langchain-go/core"
+)
+
+// DefaultCaptureLimit is the number of prompts a PromptCaptureHandler keeps
+// unless WithCaptureLimit is given.
+const DefaultCaptureLimit = 10
+
+// PromptCaptureHandler records the exact input of recent model calls, after
+// all templating and scratchpad assembly, for inspecting prompt bugs. Only
+// the most recent calls are kept. It is safe for concurrent use.
+//
+//	capture := callbacks.NewPromptCaptureHandler()
+//	executor.Invoke(ctx, input, core.WithCallbacks(capture))
+//	last, _ := capture.Last()
+//	for _, msg := range last.Messages { ... }
+type PromptCaptureHandler struct {
+	core.BaseCallbackHandler
+
+	mu       sync.Mutex
+	limit    int
+	captures []CapturedPrompt
+}
+
+// CapturedPrompt is the input of one model call.
+type CapturedPrompt struct {
+	RunID string
+	// Name is the model name reported in the start event, if any.
+	Name string
+
+	// Messages holds the messages sent to a chat model.
+	Messages []core.Message
+
+	// Prompts holds the prompts sent to a completion model.
+	Prompts []string
+}
+
+// PromptCaptureOption configures a PromptCaptureHandler.
+type PromptCaptureOption func(*PromptCaptureHandler)
+
+// WithCaptureLimit sets how many recent calls are kept. Default is
+// DefaultCaptureLimit.
+func WithCaptureLimit(n int) PromptCaptureOption {
+	return func(h *PromptCaptureHandler) { h.limit = n }
+}
+
+// NewPromptCaptureHandler creates a new PromptCaptureHandler.
+func NewPromptCaptureHandler(opts ...PromptCaptureOption) *PromptCaptureHandler {
+	h := &PromptCaptureHandler{limit: DefaultCaptureLimit}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if h.limit <= 0 {
+		h.limit = DefaultCaptureLimit
+	}
+	return h
+}
+
+func (h *PromptCaptureHandler) OnChatModelStart(_ context.Context, messages []core.Message, runID string, _ string, extras map[string]any) {
+	h.add(CapturedPrompt{
+		RunID:    runID,
+		Name:     extrasName(extras),
+		Messages: append([]core.Message(nil), messages...),
+	})
+}
+
+func (h *PromptCaptureHandler) OnLLMStart(_ context.Context, prompts []string, runID string, _ string, extras map[string]any) {
+	h.add(CapturedPrompt{
+		RunID:   runID,
+		Name:    extrasName(extras),
+		Prompts: append([]string(nil), prompts...),
+	})
+}
+
+// Captures returns the recorded calls, oldest first.
+func (h *PromptCaptureHandler) Captures() []CapturedPrompt {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]CapturedPrompt(nil), h.captures...)
+}
+
+// Last returns the most recent call, if any.
+func (h *PromptCaptureHandler) Last() (CapturedPrompt, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.captures) == 0 {
+		return CapturedPrompt{}, false
+	}
+	return h.captures[len(h.captures)-1], true
+}
+
+// Reset discards all recorded calls.
+func (h *PromptCaptureHandler) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.captures = nil
+}
+
+func (h *PromptCaptureHandler) add(c CapturedPrompt) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.captures = append(h.captures, c)
+	if over := len(h.captures) - h.limit; over > 0 {
+		h.captures = append([]CapturedPrompt(nil), h.captures[over:]...)
+	}
+}
+
+// extrasName returns the "name" entry of a start event's extras.
+func extrasName(extras map[string]any) string {
+	if n, ok := extras["name"]; ok {
+		return fmt.Sprintf("%v", n)
+	}
+	return ""
+}
+
+// Ensure PromptCaptureHandler implements CallbackHandler.
+var _ core.CallbackHandler = (*PromptCaptureHandler)(nil)
@@ -0,0 +1,73 @@
+package callbacks
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/LucaLanziani/langchain-go/core"
+)
+
+func TestPromptCaptureHandler(t *testing.T) {
+	ctx := context.Background()
+	h := NewPromptCaptureHandler(WithCaptureLimit(2))
+
+	if _, ok := h.Last(); ok {
+		t.Error("expected no captures initially")
+	}
+
+	for i := 0; i < 3; i++ {
+		messages := []core.Message{
+			core.NewSystemMessage("system"),
+			core.NewHumanMessage(fmt.Sprintf("question %d", i)),
+		}
+		h.OnChatModelStart(ctx, messages, fmt.Sprintf("run-%d", i), "", map[string]any{"name": "model"})
+		messages[1] = core.NewHumanMessage("changed")
+	}
+	h.OnLLMStart(ctx, []string{"prompt"}, "run-3", "", nil)
+
+	captures := h.Captures()
+	if len(captures) != 2 {
+		t.Fatalf("expected the last 2 captures, got %d", len(captures))
+	}
+	if captures[0].RunID != "run-2" || captures[0].Name != "model" {
+		t.Errorf("unexpected capture: %+v", captures[0])
+	}
+	if got := captures[0].Messages[1].GetContent(); got != "question 2" {
+		t.Errorf("expected captured messages to be copied, got %q", got)
+	}
+
+	last, ok := h.Last()
+	if !ok || last.RunID != "run-3" || len(last.Prompts) != 1 {
+		t.Errorf("unexpected last capture: %+v", last)
+	}
+
+	h.Reset()
+	if len(h.Captures()) != 0 {
+		t.Error("expected Reset to clear captures")
+	}
+}
+
+func TestLangSmithHandlerRecordsMessages(t *testing.T) {
+	h := NewLangSmithHandler("test")
+	h.apiKey = ""
+	messages := []core.Message{
+		core.NewHumanMessage("hi"),
+		core.NewToolMessage("42", "call_1"),
+	}
+	h.OnChatModelStart(context.Background(), messages, "run-1", "", nil)
+
+	h.mu.Lock()
+	inputs := h.runs["run-1"].Inputs
+	h.mu.Unlock()
+	got, ok := inputs["messages"].([]map[string]any)
+	if !ok || len(got) != 2 {
+		t.Fatalf("expected 2 serialized messages, got %v", inputs["messages"])
+	}
+	if got[0]["type"] != "human" || got[0]["content"] != "hi" {
+		t.Errorf("unexpected human message: %v", got[0])
+	}
+	if got[1]["tool_call_id"] != "call_1" {
+		t.Errorf("expected tool call ID, got %v", got[1])
+	}
+}
@@ -83,12 +83,37 @@ func (h *LangSmithHandler) OnLLMStart(_ context.Context, prompts []string, runID
 	h.startRun(runID, parentRunID, "LLM", "llm", map[string]any{"prompts": prompts})
 }
 
-func (h *LangSmithHandler) OnChatModelStart(_ context.Context, _ []core.Message, runID string, parentRunID string, extras map[string]any) {
+func (h *LangSmithHandler) OnChatModelStart(_ context.Context, messages []core.Message, runID string, parentRunID string, extras map[string]any) {
 	name := "ChatModel"
 	if n, ok := extras["name"]; ok {
 		name = fmt.Sprintf("%v", n)
 	}
-	h.startRun(runID, parentRunID, name, "llm", map[string]any{"messages": "..."})
+	h.startRun(runID, parentRunID, name, "llm", map[string]any{"messages": langSmithMessages(messages)})
+}
+
+// langSmithMessages converts messages to the role/content form LangSmith
+// renders, keeping tool calls and tool call IDs.
+func langSmithMessages(messages []core.Message) []map[string]any {
+	out := make([]map[string]any, len(messages))
+	for i, msg := range messages {
+		m := map[string]any{
+			"type":    string(msg.GetType()),
+			"content": msg.GetContent(),
+		}
+		if name := msg.GetName(); name != "" {
+			m["name"] = name
+		}
+		switch v := msg.(type) {
+		case *core.AIMessage:
+			if len(v.ToolCalls) > 0 {
+				m["tool_calls"] = v.ToolCalls
+			}
+		case *core.ToolMessage:
+			m["tool_call_id"] = v.ToolCallID
+		}
+		out[i] = m
+	}
+	return out
 }
 
 func (h *LangSmithHandler) OnLLMEnd(_ context.Context, output *core.LLMResult, runID string) {
@@ -0,0 +1,176 @@
+package callbacks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/LucaLanziani/langchain-go/core"
+)
+
+// MetricsHandler records latency metrics for chat model runs. For streamed
+// responses it measures time to first token (TTFT) from OnChatModelStart to
+// the first OnLLMNewToken, and the average latency between later tokens.
+// It is safe for concurrent use.
+type MetricsHandler struct {
+	core.BaseCallbackHandler
+
+	mu    sync.Mutex
+	runs  map[string]*RunMetrics
+	order []string
+	now   func() time.Time
+}
+
+// RunMetrics holds the metrics of a single chat model run.
+type RunMetrics struct {
+	RunID string
+	// Name is the model name reported in the start event, if any.
+	Name      string
+	StartTime time.Time
+
+	// TimeToFirstToken is the delay between the start of the run and its
+	// first streamed token. Zero if no tokens were streamed.
+	TimeToFirstToken time.Duration
+
+	// AvgInterTokenLatency is the mean delay between consecutive streamed
+	// tokens. Zero if fewer than two tokens were streamed.
+	AvgInterTokenLatency time.Duration
+
+	// Tokens is the number of streamed tokens (OnLLMNewToken events).
+	Tokens int
+
+	// Duration is the total run time. Zero until the run ends.
+	Duration time.Duration
+
+	// Finished reports whether the run has ended, successfully or not.
+	Finished bool
+
+	// Err is the error the run failed with, if any.
+	Err error
+
+	firstToken time.Time
+	lastToken  time.Time
+}
+
+// MetricsSnapshot is a point-in-time copy of the recorded metrics.
+type MetricsSnapshot struct {
+	// Runs lists every recorded run in start order.
+	Runs []RunMetrics
+
+	// AvgTimeToFirstToken averages TimeToFirstToken over runs that streamed
+	// at least one token.
+	AvgTimeToFirstToken time.Duration
+
+	// AvgInterTokenLatency averages the inter-token latency over all token
+	// gaps across runs.
+	AvgInterTokenLatency time.Duration
+}
+
+// NewMetricsHandler creates a new MetricsHandler.
+func NewMetricsHandler() *MetricsHandler {
+	return &MetricsHandler{
+		runs: make(map[string]*RunMetrics),
+		now:  time.Now,
+	}
+}
+
+func (h *MetricsHandler) OnChatModelStart(_ context.Context, _ []core.Message, runID string, _ string, extras map[string]any) {
+	h.start(runID, extras)
+}
+
+func (h *MetricsHandler) OnLLMStart(_ context.Context, _ []string, runID string, _ string, extras map[string]any) {
+	h.start(runID, extras)
+}
+
+func (h *MetricsHandler) OnLLMNewToken(_ context.Context, _ string, runID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	run, ok := h.runs[runID]
+	if !ok {
+		return
+	}
+	now := h.now()
+	if run.Tokens == 0 {
+		run.firstToken = now
+		run.TimeToFirstToken = now.Sub(run.StartTime)
+	}
+	run.lastToken = now
+	run.Tokens++
+	if run.Tokens > 1 {
+		run.AvgInterTokenLatency = run.lastToken.Sub(run.firstToken) / time.Duration(run.Tokens-1)
+	}
+}
+
+func (h *MetricsHandler) OnLLMEnd(_ context.Context, _ *core.LLMResult, runID string) {
+	h.end(runID, nil)
+}
+
+func (h *MetricsHandler) OnLLMError(_ context.Context, err error, runID string) {
+	h.end(runID, err)
+}
+
+// Snapshot returns a copy of the metrics recorded so far.
+func (h *MetricsHandler) Snapshot() MetricsSnapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	snap := MetricsSnapshot{Runs: make([]RunMetrics, 0, len(h.order))}
+	var ttftSum, gapSum time.Duration
+	var ttftRuns, gaps int
+	for _, id := range h.order {
+		run := h.runs[id]
+		snap.Runs = append(snap.Runs, *run)
+		if run.Tokens > 0 {
+			ttftSum += run.TimeToFirstToken
+			ttftRuns++
+		}
+		if run.Tokens > 1 {
+			gapSum += run.lastToken.Sub(run.firstToken)
+			gaps += run.Tokens - 1
+		}
+	}
+	if ttftRuns > 0 {
+		snap.AvgTimeToFirstToken = ttftSum / time.Duration(ttftRuns)
+	}
+	if gaps > 0 {
+		snap.AvgInterTokenLatency = gapSum / time.Duration(gaps)
+	}
+	return snap
+}
+
+// Reset discards all recorded metrics.
+func (h *MetricsHandler) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.runs = make(map[string]*RunMetrics)
+	h.order = nil
+}
+
+func (h *MetricsHandler) start(runID string, extras map[string]any) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	run := &RunMetrics{RunID: runID, StartTime: h.now()}
+	if n, ok := extras["name"]; ok {
+		run.Name = fmt.Sprintf("%v", n)
+	}
+	if _, exists := h.runs[runID]; !exists {
+		h.order = append(h.order, runID)
+	}
+	h.runs[runID] = run
+}
+
+func (h *MetricsHandler) end(runID string, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	run, ok := h.runs[runID]
+	if !ok || run.Finished {
+		return
+	}
+	run.Duration = h.now().Sub(run.StartTime)
+	run.Finished = true
+	run.Err = err
+}
+
+// Ensure MetricsHandler implements CallbackHandler.
+var _ core.CallbackHandler = (*MetricsHandler)(nil)
@@ -0,0 +1,88 @@
+package callbacks
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeClock returns times that advance only when told to.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+func newFakeClock() *fakeClock               { return &fakeClock{t: time.Unix(0, 0)} }
+func newTestMetricsHandler(c *fakeClock) *MetricsHandler {
+	h := NewMetricsHandler()
+	h.now = c.now
+	return h
+}
+
+func TestMetricsHandlerStreamingRun(t *testing.T) {
+	ctx := context.Background()
+	clock := newFakeClock()
+	h := newTestMetricsHandler(clock)
+
+	h.OnChatModelStart(ctx, nil, "run-1", "", map[string]any{"name": "ChatOpenAI"})
+	clock.advance(300 * time.Millisecond)
+	h.OnLLMNewToken(ctx, "a", "run-1")
+	clock.advance(20 * time.Millisecond)
+	h.OnLLMNewToken(ctx, "b", "run-1")
+	clock.advance(40 * time.Millisecond)
+	h.OnLLMNewToken(ctx, "c", "run-1")
+	clock.advance(10 * time.Millisecond)
+	h.OnLLMEnd(ctx, nil, "run-1")
+
+	snap := h.Snapshot()
+	if len(snap.Runs) != 1 {
+		t.Fatalf("expected 1 run, got %d", len(snap.Runs))
+	}
+	run := snap.Runs[0]
+	if run.Name != "ChatOpenAI" || run.Tokens != 3 || !run.Finished {
+		t.Errorf("unexpected run: %+v", run)
+	}
+	if run.TimeToFirstToken != 300*time.Millisecond {
+		t.Errorf("expected TTFT 300ms, got %v", run.TimeToFirstToken)
+	}
+	if run.AvgInterTokenLatency != 30*time.Millisecond {
+		t.Errorf("expected inter-token latency 30ms, got %v", run.AvgInterTokenLatency)
+	}
+	if run.Duration != 370*time.Millisecond {
+		t.Errorf("expected duration 370ms, got %v", run.Duration)
+	}
+	if snap.AvgTimeToFirstToken != 300*time.Millisecond || snap.AvgInterTokenLatency != 30*time.Millisecond {
+		t.Errorf("unexpected aggregates: %+v", snap)
+	}
+}
+
+func TestMetricsHandlerAggregates(t *testing.T) {
+	ctx := context.Background()
+	clock := newFakeClock()
+	h := newTestMetricsHandler(clock)
+
+	h.OnChatModelStart(ctx, nil, "a", "", nil)
+	h.OnChatModelStart(ctx, nil, "b", "", nil)
+	h.OnChatModelStart(ctx, nil, "c", "", nil)
+	clock.advance(100 * time.Millisecond)
+	h.OnLLMNewToken(ctx, "x", "a")
+	clock.advance(100 * time.Millisecond)
+	h.OnLLMNewToken(ctx, "x", "b")
+	h.OnLLMError(ctx, errors.New("boom"), "c")
+
+	snap := h.Snapshot()
+	if len(snap.Runs) != 3 || snap.Runs[0].RunID != "a" || snap.Runs[2].RunID != "c" {
+		t.Fatalf("expected runs in start order, got %+v", snap.Runs)
+	}
+	if snap.AvgTimeToFirstToken != 150*time.Millisecond {
+		t.Errorf("expected average TTFT 150ms, got %v", snap.AvgTimeToFirstToken)
+	}
+	if snap.Runs[2].Err == nil || snap.Runs[2].Tokens != 0 {
+		t.Errorf("expected failed run without tokens, got %+v", snap.Runs[2])
+	}
+
+	h.Reset()
+	if len(h.Snapshot().Runs) != 0 {
+		t.Error("expected no runs after Reset")
+	}
+}
@@ -12,17 +12,47 @@ import (
 	"github.com/LucaLanziani/langchain-go/retrievers"
 )
 
+// Chain is a Runnable over a map of named inputs that declares which keys it
+// requires and which keys it produces.
+type Chain interface {
+	core.Runnable[map[string]any, string]
+
+	// InputKeys returns the keys that must be present in the input map.
+	InputKeys() []string
+
+	// OutputKeys returns the keys describing the chain's output.
+	OutputKeys() []string
+
+	// ValidateInput checks that all required input keys are present.
+	ValidateInput(input map[string]any) error
+}
+
+// validateInputKeys returns an error listing any keys missing from input.
+func validateInputKeys(chainName string, keys []string, input map[string]any) error {
+	var missing []string
+	for _, k := range keys {
+		if _, ok := input[k]; !ok {
+			missing = append(missing, k)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%s: missing input keys: %s", chainName, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
 // LLMChain is the simplest chain: prompt -> model -> output.
 // It implements Runnable[map[string]any, string].
 type LLMChain struct {
-	prompt *prompts.ChatPromptTemplate
-	llm    llms.ChatModel
-	name   string
+	prompt    *prompts.ChatPromptTemplate
+	llm       llms.ChatModel
+	outputKey string
+	name      string
 }
 
 // NewLLMChain creates a new LLMChain.
 func NewLLMChain(llm llms.ChatModel, prompt *prompts.ChatPromptTemplate) *LLMChain {
-	return &LLMChain{prompt: prompt, llm: llm}
+	return &LLMChain{prompt: prompt, llm: llm, outputKey: "text"}
 }
 
 // GetName returns the chain name.
@@ -33,14 +63,50 @@ func (c *LLMChain) GetName() string {
 	return "LLMChain"
 }
 
-// Invoke runs the chain.
+// InputKeys returns the prompt variables that must be supplied by the caller.
+// Placeholder variables are optional and partial variables are pre-filled,
+// so neither is included.
+func (c *LLMChain) InputKeys() []string {
+	optional := make(map[string]bool)
+	for _, msg := range c.prompt.Messages {
+		if msg.Role == "placeholder" {
+			optional[msg.Template] = true
+		}
+	}
+	for k := range c.prompt.PartialVariables {
+		optional[k] = true
+	}
+	var keys []string
+	for _, v := range c.prompt.InputVariables {
+		if !optional[v] {
+			keys = append(keys, v)
+		}
+	}
+	return keys
+}
+
+// OutputKeys returns the output keys.
+func (c *LLMChain) OutputKeys() []string {
+	return []string{c.outputKey}
+}
+
+// ValidateInput checks that all required prompt variables are present.
+func (c *LLMChain) ValidateInput(input map[string]any) error {
+	return validateInputKeys(c.GetName(), c.InputKeys(), input)
+}
+
+// Invoke runs the chain. The model call runs as a child of the chain's run.
 func (c *LLMChain) Invoke(ctx context.Context, input map[string]any, opts ...core.Option) (string, error) {
+	cfg := core.ApplyOptions(opts...)
+	if err := c.ValidateInput(input); err != nil {
+		return "", err
+	}
 	messages, err := c.prompt.FormatMessages(input)
 	if err != nil {
 		return "", fmt.Errorf("prompt format error: %w", err)
 	}
 
-	response, err := c.llm.Invoke(ctx, messages, opts...)
+	response, err := c.llm.Invoke(ctx, messages, core.ChildOptions(cfg, 0, opts...)...)
 	if err != nil {
 		return "", fmt.Errorf("LLM error: %w", err)
 	}
@@ -50,41 +116,68 @@ func (c *LLMChain) Invoke(ctx context.Context, input map[string]any, opts ...cor
 
 // Stream runs the chain with streaming output.
 func (c *LLMChain) Stream(ctx context.Context, input map[string]any, opts ...core.Option) (*core.StreamIterator[string], error) {
+	cfg := core.ApplyOptions(opts...)
+	if err := c.ValidateInput(input); err != nil {
+		return nil, err
+	}
 	messages, err := c.prompt.FormatMessages(input)
 	if err != nil {
 		return nil, fmt.Errorf("prompt format error: %w", err)
 	}
 
-	stream, err := c.llm.Stream(ctx, messages, opts...)
+	stream, err := c.llm.Stream(ctx, messages, core.ChildOptions(cfg, 0, opts...)...)
 	if err != nil {
 		return nil, fmt.Errorf("LLM stream error: %w", err)
 	}
 
-	// Transform AI message chunks to strings.
+	// Transform AI message chunks to strings. Closing the returned iterator
+	// also closes the upstream model stream.
 	outCh := make(chan core.StreamChunk[string], 64)
+	out := core.NewStreamIterator(outCh)
+	finished := make(chan struct{})
+	go func() {
+		select {
+		case <-out.Done():
+			stream.Close()
+		case <-finished:
+		}
+	}()
 	go func() {
 		defer close(outCh)
+		defer close(finished)
+		defer stream.Close()
 		for {
 			msg, ok, err := stream.Next()
 			if err != nil {
-				outCh <- core.StreamChunk[string]{Err: err}
+				core.Send(out.Done(), outCh, core.StreamChunk[string]{Err: err})
 				return
 			}
 			if !ok {
 				return
 			}
-			outCh <- core.StreamChunk[string]{Value: msg.Content}
+			// Chunks carrying only tool calls or usage have no text.
+			if msg.Content == "" {
+				continue
+			}
+			if !core.Send(out.Done(), outCh, core.StreamChunk[string]{Value: msg.Content}) {
+				return
+			}
 		}
 	}()
 
-	return core.NewStreamIterator(outCh), nil
+	return out, nil
 }
 
-// Batch runs the chain for multiple inputs.
+// Batch runs the chain for multiple inputs. Item i runs with the RunID
+// core.ChildRunID(runID, i), where runID is the batch's own run ID.
 func (c *LLMChain) Batch(ctx context.Context, inputs []map[string]any, opts ...core.Option) ([]string, error) {
+	cfg := core.ApplyOptions(opts...)
 	results := make([]string, len(inputs))
 	for i, input := range inputs {
-		result, err := c.Invoke(ctx, input, opts...)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		result, err := c.Invoke(ctx, input, core.ChildOptions(cfg, i, opts...)...)
 		if err != nil {
 			return nil, fmt.Errorf("batch item %d: %w", i, err)
 		}
@@ -99,6 +192,7 @@ type StuffDocumentsChain struct {
 	llmChain    *LLMChain
 	documentKey string
 	inputKey    string
+	outputKey   string
 	separator   string
 	name        string
 }
@@ -109,6 +203,7 @@ func NewStuffDocumentsChain(llmChain *LLMChain) *StuffDocumentsChain {
 		llmChain:    llmChain,
 		documentKey: "context",
 		inputKey:    "input_documents",
+		outputKey:   "output_text",
 		separator:   "\n\n",
 	}
 }
@@ -121,15 +216,37 @@ func (c *StuffDocumentsChain) GetName() string {
 	return "StuffDocumentsChain"
 }
 
-// Invoke runs the chain with documents.
-func (c *StuffDocumentsChain) Invoke(ctx context.Context, input map[string]any, opts ...core.Option) (string, error) {
-	docsRaw, ok := input[c.inputKey]
-	if !ok {
-		return "", fmt.Errorf("missing input key %q", c.inputKey)
+// InputKeys returns the documents key plus the wrapped LLMChain's keys,
+// excluding the context key that this chain fills in.
+func (c *StuffDocumentsChain) InputKeys() []string {
+	keys := []string{c.inputKey}
+	for _, k := range c.llmChain.InputKeys() {
+		if k != c.documentKey && k != c.inputKey {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// OutputKeys returns the output keys.
+func (c *StuffDocumentsChain) OutputKeys() []string {
+	return []string{c.outputKey}
+}
+
+// ValidateInput checks that the documents and all prompt variables are present.
+func (c *StuffDocumentsChain) ValidateInput(input map[string]any) error {
+	return validateInputKeys(c.GetName(), c.InputKeys(), input)
+}
+
+// prepareInput returns a copy of input with the documents combined under
+// the document key.
+func (c *StuffDocumentsChain) prepareInput(input map[string]any) (map[string]any, error) {
+	if err := c.ValidateInput(input); err != nil {
+		return nil, err
 	}
-	docs, ok := docsRaw.([]*core.Document)
+	docs, ok := input[c.inputKey].([]*core.Document)
 	if !ok {
-		return "", fmt.Errorf("input key %q must be []*core.Document", c.inputKey)
+		return nil, fmt.Errorf("input key %q must be []*core.Document", c.inputKey)
 	}
 
 	// Combine document contents.
@@ -145,27 +262,38 @@ func (c *StuffDocumentsChain) Invoke(ctx context.Context, input map[string]any,
 		mergedInput[k] = v
 	}
 	mergedInput[c.documentKey] = combinedContext
+	return mergedInput, nil
+}
 
-	return c.llmChain.Invoke(ctx, mergedInput, opts...)
+// Invoke runs the chain with documents.
+func (c *StuffDocumentsChain) Invoke(ctx context.Context, input map[string]any, opts ...core.Option) (string, error) {
+	cfg := core.ApplyOptions(opts...)
+	mergedInput, err := c.prepareInput(input)
+	if err != nil {
+		return "", err
+	}
+	return c.llmChain.Invoke(ctx, mergedInput, core.ChildOptions(cfg, 0, opts...)...)
 }
 
-// Stream streams the chain output.
+// Stream streams the wrapped LLM chain's output.
 func (c *StuffDocumentsChain) Stream(ctx context.Context, input map[string]any, opts ...core.Option) (*core.StreamIterator[string], error) {
-	result, err := c.Invoke(ctx, input, opts...)
+	cfg := core.ApplyOptions(opts...)
+	mergedInput, err := c.prepareInput(input)
 	if err != nil {
 		return nil, err
 	}
-	ch := make(chan core.StreamChunk[string], 1)
-	ch <- core.StreamChunk[string]{Value: result}
-	close(ch)
-	return core.NewStreamIterator(ch), nil
+	return c.llmChain.Stream(ctx, mergedInput, core.ChildOptions(cfg, 0, opts...)...)
 }
 
 // Batch runs the chain for multiple inputs.
 func (c *StuffDocumentsChain) Batch(ctx context.Context, inputs []map[string]any, opts ...core.Option) ([]string, error) {
+	cfg := core.ApplyOptions(opts...)
 	results := make([]string, len(inputs))
 	for i, input := range inputs {
-		result, err := c.Invoke(ctx, input, opts...)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		result, err := c.Invoke(ctx, input, core.ChildOptions(cfg, i, opts...)...)
 		if err != nil {
 			return nil, fmt.Errorf("batch item %d: %w", i, err)
 		}
@@ -174,12 +302,36 @@ func (c *StuffDocumentsChain) Batch(ctx context.Context, inputs []map[string]any
 	return results, nil
 }
 
+// RetrievalQAEventType identifies the kind of a RetrievalQAChunk.
+type RetrievalQAEventType string
+
+const (
+	// RetrievalQAEventSources carries the retrieved documents. It is always
+	// the first event.
+	RetrievalQAEventSources RetrievalQAEventType = "sources"
+
+	// RetrievalQAEventToken carries a piece of the streamed answer.
+	RetrievalQAEventToken RetrievalQAEventType = "token"
+)
+
+// RetrievalQAChunk is an event from RetrievalQA.StreamWithSources.
+type RetrievalQAChunk struct {
+	Type RetrievalQAEventType
+
+	// Sources holds the retrieved documents of a sources event.
+	Sources []*core.Document
+
+	// Token holds the answer text of a token event.
+	Token string
+}
+
 // RetrievalQA combines a retriever with an LLM to answer questions.
 // It retrieves relevant documents and uses them as context.
 type RetrievalQA struct {
 	retriever retrievers.Retriever
 	chain     *StuffDocumentsChain
 	queryKey  string
+	outputKey string
 	name      string
 }
 
@@ -189,6 +341,7 @@ func NewRetrievalQA(retriever retrievers.Retriever, llmChain *LLMChain) *Retriev
 		retriever: retriever,
 		chain:     NewStuffDocumentsChain(llmChain),
 		queryKey:  "query",
+		outputKey: "result",
 	}
 }
 
@@ -200,39 +353,143 @@ func (r *RetrievalQA) GetName() string {
 	return "RetrievalQA"
 }
 
-// Invoke retrieves documents and answers the query.
-func (r *RetrievalQA) Invoke(ctx context.Context, input map[string]any, opts ...core.Option) (string, error) {
-	query, ok := input[r.queryKey]
-	if !ok {
-		return "", fmt.Errorf("missing input key %q", r.queryKey)
+// InputKeys returns the query key plus any prompt variables the combine
+// chain needs beyond the retrieved documents.
+func (r *RetrievalQA) InputKeys() []string {
+	keys := []string{r.queryKey}
+	for _, k := range r.chain.InputKeys() {
+		if k != r.queryKey && k != r.chain.inputKey {
+			keys = append(keys, k)
+		}
 	}
+	return keys
+}
+
+// OutputKeys returns the output keys.
+func (r *RetrievalQA) OutputKeys() []string {
+	return []string{r.outputKey}
+}
 
+// ValidateInput checks that the query and all prompt variables are present.
+func (r *RetrievalQA) ValidateInput(input map[string]any) error {
+	return validateInputKeys(r.GetName(), r.InputKeys(), input)
+}
+
+// retrieve fetches documents for the query and returns them along with a
+// copy of input that carries them for the combine chain.
+func (r *RetrievalQA) retrieve(ctx context.Context, input map[string]any) (map[string]any, []*core.Document, error) {
+	query := input[r.queryKey]
 	docs, err := r.retriever.GetRelevantDocuments(ctx, fmt.Sprintf("%v", query))
 	if err != nil {
-		return "", fmt.Errorf("retrieval error: %w", err)
+		return nil, nil, fmt.Errorf("retrieval error: %w", err)
+	}
+
+	mergedInput := make(map[string]any, len(input)+1)
+	for k, v := range input {
+		mergedInput[k] = v
 	}
+	mergedInput[r.chain.inputKey] = docs
+	return mergedInput, docs, nil
+}
 
-	input["input_documents"] = docs
-	return r.chain.Invoke(ctx, input, opts...)
+// Invoke retrieves documents and answers the query.
+func (r *RetrievalQA) Invoke(ctx context.Context, input map[string]any, opts ...core.Option) (string, error) {
+	cfg := core.ApplyOptions(opts...)
+	if err := r.ValidateInput(input); err != nil {
+		return "", err
+	}
+	mergedInput, _, err := r.retrieve(ctx, input)
+	if err != nil {
+		return "", err
+	}
+	return r.chain.Invoke(ctx, mergedInput, core.ChildOptions(cfg, 0, opts...)...)
 }
 
-// Stream streams the chain output.
+// Stream retrieves documents for the query and streams the answer tokens.
+// Use StreamWithSources to also receive the retrieved documents.
 func (r *RetrievalQA) Stream(ctx context.Context, input map[string]any, opts ...core.Option) (*core.StreamIterator[string], error) {
-	result, err := r.Invoke(ctx, input, opts...)
+	cfg := core.ApplyOptions(opts...)
+	if err := r.ValidateInput(input); err != nil {
+		return nil, err
+	}
+	mergedInput, _, err := r.retrieve(ctx, input)
 	if err != nil {
 		return nil, err
 	}
-	ch := make(chan core.StreamChunk[string], 1)
-	ch <- core.StreamChunk[string]{Value: result}
-	close(ch)
-	return core.NewStreamIterator(ch), nil
+	return r.chain.Stream(ctx, mergedInput, core.ChildOptions(cfg, 0, opts...)...)
+}
+
+// StreamWithSources returns immediately and runs retrieval in the
+// background. The stream's first event holds the retrieved documents, so a
+// UI can show "found N sources"; the answer tokens follow. Retrieval and
+// model errors are delivered through the stream. Closing the stream cancels
+// an in-flight retrieval or model call.
+func (r *RetrievalQA) StreamWithSources(ctx context.Context, input map[string]any, opts ...core.Option) (*core.StreamIterator[RetrievalQAChunk], error) {
+	cfg := core.ApplyOptions(opts...)
+	if err := r.ValidateInput(input); err != nil {
+		return nil, err
+	}
+
+	ch := make(chan core.StreamChunk[RetrievalQAChunk], 64)
+	out := core.NewStreamIterator(ch)
+	streamCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-out.Done():
+			cancel()
+		case <-streamCtx.Done():
+		}
+	}()
+	go func() {
+		defer close(ch)
+		defer cancel()
+		fail := func(err error) {
+			core.Send(out.Done(), ch, core.StreamChunk[RetrievalQAChunk]{Err: err})
+		}
+
+		mergedInput, docs, err := r.retrieve(streamCtx, input)
+		if err != nil {
+			fail(err)
+			return
+		}
+		sources := RetrievalQAChunk{Type: RetrievalQAEventSources, Sources: docs}
+		if !core.Send(out.Done(), ch, core.StreamChunk[RetrievalQAChunk]{Value: sources}) {
+			return
+		}
+
+		tokens, err := r.chain.Stream(streamCtx, mergedInput, core.ChildOptions(cfg, 0, opts...)...)
+		if err != nil {
+			fail(err)
+			return
+		}
+		defer tokens.Close()
+		for {
+			token, ok, err := tokens.Next()
+			if err != nil {
+				fail(err)
+				return
+			}
+			if !ok {
+				return
+			}
+			chunk := RetrievalQAChunk{Type: RetrievalQAEventToken, Token: token}
+			if !core.Send(out.Done(), ch, core.StreamChunk[RetrievalQAChunk]{Value: chunk}) {
+				return
+			}
+		}
+	}()
+	return out, nil
 }
 
 // Batch runs the chain for multiple inputs.
 func (r *RetrievalQA) Batch(ctx context.Context, inputs []map[string]any, opts ...core.Option) ([]string, error) {
+	cfg := core.ApplyOptions(opts...)
 	results := make([]string, len(inputs))
 	for i, input := range inputs {
-		result, err := r.Invoke(ctx, input, opts...)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		result, err := r.Invoke(ctx, input, core.ChildOptions(cfg, i, opts...)...)
 		if err != nil {
 			return nil, fmt.Errorf("batch item %d: %w", i, err)
 		}
@@ -240,3 +497,10 @@ func (r *RetrievalQA) Batch(ctx context.Context, inputs []map[string]any, opts .
 	}
 	return results, nil
 }
+
+// Ensure chains implement Chain.
+var (
+	_ Chain = (*LLMChain)(nil)
+	_ Chain = (*StuffDocumentsChain)(nil)
+	_ Chain = (*RetrievalQA)(nil)
+)
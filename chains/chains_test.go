@@ -0,0 +1,342 @@
+package chains
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LucaLanziani/langchain-go/core"
+	"github.com/LucaLanziani/langchain-go/llms"
+	"github.com/LucaLanziani/langchain-go/prompts"
+	"github.com/LucaLanziani/langchain-go/retrievers"
+)
+
+// fakeChatModel is a test helper that returns a fixed response and records
+// the last messages it received.
+type fakeChatModel struct {
+	response string
+	last     []core.Message
+	configs  []*core.RunnableConfig
+}
+
+func (m *fakeChatModel) GetName() string { return "fake" }
+func (m *fakeChatModel) Invoke(_ context.Context, input []core.Message, opts ...core.Option) (*core.AIMessage, error) {
+	m.last = input
+	m.configs = append(m.configs, core.ApplyOptions(opts...))
+	return core.NewAIMessage(m.response), nil
+}
+func (m *fakeChatModel) Stream(ctx context.Context, input []core.Message, opts ...core.Option) (*core.StreamIterator[*core.AIMessage], error) {
+	msg, err := m.Invoke(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan core.StreamChunk[*core.AIMessage], 1)
+	ch <- core.StreamChunk[*core.AIMessage]{Value: msg}
+	close(ch)
+	return core.NewStreamIterator(ch), nil
+}
+func (m *fakeChatModel) Batch(ctx context.Context, inputs [][]core.Message, opts ...core.Option) ([]*core.AIMessage, error) {
+	results := make([]*core.AIMessage, len(inputs))
+	for i, in := range inputs {
+		r, err := m.Invoke(ctx, in, opts...)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = r
+	}
+	return results, nil
+}
+func (m *fakeChatModel) Generate(ctx context.Context, input []core.Message, opts ...core.Option) (*llms.ChatResult, error) {
+	msg, err := m.Invoke(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &llms.ChatResult{Generations: []*llms.ChatGeneration{{Message: msg}}}, nil
+}
+func (m *fakeChatModel) BindTools(...llms.ToolDefinition) llms.ChatModel    { return m }
+func (m *fakeChatModel) WithStructuredOutput(map[string]any) llms.ChatModel { return m }
+
+// fakeRetriever returns a fixed set of documents.
+type fakeRetriever struct {
+	docs []*core.Document
+}
+
+func (r *fakeRetriever) GetName() string { return "fakeRetriever" }
+func (r *fakeRetriever) GetRelevantDocuments(_ context.Context, _ string, _ ...retrievers.Option) ([]*core.Document, error) {
+	return r.docs, nil
+}
+func (r *fakeRetriever) Invoke(ctx context.Context, input string, _ ...core.Option) ([]*core.Document, error) {
+	return r.GetRelevantDocuments(ctx, input)
+}
+func (r *fakeRetriever) Stream(ctx context.Context, input string, _ ...core.Option) (*core.StreamIterator[[]*core.Document], error) {
+	ch := make(chan core.StreamChunk[[]*core.Document], 1)
+	ch <- core.StreamChunk[[]*core.Document]{Value: r.docs}
+	close(ch)
+	return core.NewStreamIterator(ch), nil
+}
+func (r *fakeRetriever) Batch(ctx context.Context, inputs []string, _ ...core.Option) ([][]*core.Document, error) {
+	results := make([][]*core.Document, len(inputs))
+	for i := range inputs {
+		results[i] = r.docs
+	}
+	return results, nil
+}
+
+func TestLLMChainInputKeys(t *testing.T) {
+	prompt := prompts.NewChatPromptTemplate(
+		prompts.System("You are {persona}."),
+		prompts.Placeholder("history"),
+		prompts.Human("{question} in {language}"),
+	).WithPartialVariables(map[string]any{"language": "English"})
+	chain := NewLLMChain(&fakeChatModel{}, prompt)
+
+	keys := chain.InputKeys()
+	if strings.Join(keys, ",") != "persona,question" {
+		t.Errorf("expected [persona question], got %v", keys)
+	}
+	if out := chain.OutputKeys(); len(out) != 1 || out[0] != "text" {
+		t.Errorf("expected [text], got %v", out)
+	}
+}
+
+func TestLLMChainValidateInput(t *testing.T) {
+	prompt := prompts.NewChatPromptTemplate(prompts.Human("{a} and {b}"))
+	chain := NewLLMChain(&fakeChatModel{response: "ok"}, prompt)
+
+	err := chain.ValidateInput(map[string]any{"a": 1})
+	if err == nil || !strings.Contains(err.Error(), "b") {
+		t.Fatalf("expected missing key error mentioning b, got %v", err)
+	}
+
+	if _, err := chain.Invoke(context.Background(), map[string]any{"a": 1}); err == nil {
+		t.Error("expected Invoke to fail validation")
+	}
+
+	result, err := chain.Invoke(context.Background(), map[string]any{"a": 1, "b": 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != "ok" {
+		t.Errorf("expected 'ok', got %q", result)
+	}
+}
+
+func TestLLMChainChildRunIDs(t *testing.T) {
+	model := &fakeChatModel{response: "ok"}
+	chain := NewLLMChain(model, prompts.NewChatPromptTemplate(prompts.Human("{q}")))
+	ctx := context.Background()
+
+	if _, err := chain.Invoke(ctx, map[string]any{"q": "a"}, core.WithRunID("run-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := chain.Batch(ctx, []map[string]any{{"q": "a"}, {"q": "b"}}, core.WithRunID("run-2")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(model.configs) != 3 {
+		t.Fatalf("expected 3 model calls, got %d", len(model.configs))
+	}
+	if cfg := model.configs[0]; cfg.ParentRunID != "run-1" || cfg.RunID != core.ChildRunID("run-1", 0) {
+		t.Errorf("expected a child run of run-1, got %s under %s", cfg.RunID, cfg.ParentRunID)
+	}
+	for i, cfg := range model.configs[1:] {
+		item := core.ChildRunID("run-2", i)
+		if cfg.ParentRunID != item || cfg.RunID != core.ChildRunID(item, 0) {
+			t.Errorf("batch item %d: expected a child run of %s, got %s under %s", i, item, cfg.RunID, cfg.ParentRunID)
+		}
+	}
+}
+
+func TestRetrievalQAInputKeys(t *testing.T) {
+	prompt := prompts.NewChatPromptTemplate(
+		prompts.System("Context: {context}"),
+		prompts.Human("{query}"),
+	)
+	qa := NewRetrievalQA(&fakeRetriever{}, NewLLMChain(&fakeChatModel{}, prompt))
+
+	keys := qa.InputKeys()
+	if len(keys) != 1 || keys[0] != "query" {
+		t.Errorf("expected [query], got %v", keys)
+	}
+	if err := qa.ValidateInput(map[string]any{}); err == nil {
+		t.Error("expected error for missing query")
+	}
+}
+
+func TestRetrievalQAInvoke(t *testing.T) {
+	model := &fakeChatModel{response: "answer"}
+	prompt := prompts.NewChatPromptTemplate(
+		prompts.System("Context: {context}"),
+		prompts.Human("{query}"),
+	)
+	retriever := &fakeRetriever{docs: []*core.Document{core.NewDocument("doc one"), core.NewDocument("doc two")}}
+	qa := NewRetrievalQA(retriever, NewLLMChain(model, prompt))
+
+	result, err := qa.Invoke(context.Background(), map[string]any{"query": "q"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != "answer" {
+		t.Errorf("expected 'answer', got %q", result)
+	}
+	if got := model.last[0].GetContent(); got != "Context: doc one\n\ndoc two" {
+		t.Errorf("unexpected system message: %q", got)
+	}
+}
+
+func TestRetrievalQAStream(t *testing.T) {
+	model := &fakeChatModel{response: "answer"}
+	prompt := prompts.NewChatPromptTemplate(
+		prompts.System("Context: {context}"),
+		prompts.Human("{query}"),
+	)
+	retriever := &fakeRetriever{docs: []*core.Document{core.NewDocument("doc one"), core.NewDocument("doc two")}}
+	qa := NewRetrievalQA(retriever, NewLLMChain(model, prompt))
+
+	stream, err := qa.Stream(context.Background(), map[string]any{"query": "q"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tokens, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(tokens, ""); got != "answer" {
+		t.Errorf("expected 'answer', got %q", got)
+	}
+}
+
+func TestRetrievalQAChildRunIDs(t *testing.T) {
+	model := &fakeChatModel{response: "answer"}
+	prompt := prompts.NewChatPromptTemplate(prompts.System("{context}"), prompts.Human("{query}"))
+	qa := NewRetrievalQA(&fakeRetriever{}, NewLLMChain(model, prompt))
+	input := map[string]any{"query": "q"}
+
+	if _, err := qa.Invoke(context.Background(), input, core.WithRunID("run-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stream, err := qa.Stream(context.Background(), input, core.WithRunID("run-1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := stream.Collect(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(model.configs) != 2 {
+		t.Fatalf("expected 2 model calls, got %d", len(model.configs))
+	}
+	// RetrievalQA -> StuffDocumentsChain -> LLMChain -> model.
+	llmRun := core.ChildRunID(core.ChildRunID("run-1", 0), 0)
+	for i, cfg := range model.configs {
+		if cfg.ParentRunID != llmRun || cfg.RunID != core.ChildRunID(llmRun, 0) {
+			t.Errorf("call %d: expected a child run of %s, got %s under %s", i, llmRun, cfg.RunID, cfg.ParentRunID)
+		}
+	}
+}
+
+func TestRetrievalQAStreamWithSources(t *testing.T) {
+	model := &fakeChatModel{response: "answer"}
+	prompt := prompts.NewChatPromptTemplate(
+		prompts.System("Context: {context}"),
+		prompts.Human("{query}"),
+	)
+	retriever := &fakeRetriever{docs: []*core.Document{core.NewDocument("doc one"), core.NewDocument("doc two")}}
+	qa := NewRetrievalQA(retriever, NewLLMChain(model, prompt))
+
+	stream, err := qa.StreamWithSources(context.Background(), map[string]any{"query": "q"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	chunks, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(chunks) < 2 {
+		t.Fatalf("expected a sources event and answer tokens, got %+v", chunks)
+	}
+	if chunks[0].Type != RetrievalQAEventSources || len(chunks[0].Sources) != 2 {
+		t.Errorf("expected 2 sources first, got %+v", chunks[0])
+	}
+	var answer string
+	for _, c := range chunks[1:] {
+		if c.Type != RetrievalQAEventToken {
+			t.Errorf("expected token event, got %+v", c)
+		}
+		answer += c.Token
+	}
+	if answer != "answer" {
+		t.Errorf("expected 'answer', got %q", answer)
+	}
+}
+
+// blockingRetriever blocks until its context is canceled and reports the
+// context error on canceled.
+type blockingRetriever struct {
+	fakeRetriever
+	started  chan struct{}
+	canceled chan error
+}
+
+func (r *blockingRetriever) GetRelevantDocuments(ctx context.Context, _ string, _ ...retrievers.Option) ([]*core.Document, error) {
+	close(r.started)
+	<-ctx.Done()
+	r.canceled <- ctx.Err()
+	return nil, ctx.Err()
+}
+
+func TestRetrievalQAStreamWithSourcesCloseCancelsRetrieval(t *testing.T) {
+	prompt := prompts.NewChatPromptTemplate(prompts.System("{context}"), prompts.Human("{query}"))
+	retriever := &blockingRetriever{started: make(chan struct{}), canceled: make(chan error, 1)}
+	qa := NewRetrievalQA(retriever, NewLLMChain(&fakeChatModel{}, prompt))
+
+	stream, err := qa.StreamWithSources(context.Background(), map[string]any{"query": "q"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	<-retriever.started
+	stream.Close()
+
+	select {
+	case err := <-retriever.canceled:
+		if err != context.Canceled {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("retrieval was not canceled when the stream was closed")
+	}
+}
+
+// chunkedChatModel streams a fixed sequence of messages.
+type chunkedChatModel struct {
+	fakeChatModel
+	chunks []*core.AIMessage
+}
+
+func (m *chunkedChatModel) Stream(context.Context, []core.Message, ...core.Option) (*core.StreamIterator[*core.AIMessage], error) {
+	ch := make(chan core.StreamChunk[*core.AIMessage], len(m.chunks))
+	for _, c := range m.chunks {
+		ch <- core.StreamChunk[*core.AIMessage]{Value: c}
+	}
+	close(ch)
+	return core.NewStreamIterator(ch), nil
+}
+
+func TestLLMChainStreamSkipsEmptyChunks(t *testing.T) {
+	usage := core.NewAIMessage("")
+	usage.UsageMetadata = &core.UsageMetadata{TotalTokens: 5}
+	model := &chunkedChatModel{chunks: []*core.AIMessage{core.NewAIMessage("Hel"), core.NewAIMessage(""), core.NewAIMessage("lo"), usage}}
+	chain := NewLLMChain(model, prompts.NewChatPromptTemplate(prompts.Human("{q}")))
+
+	stream, err := chain.Stream(context.Background(), map[string]any{"q": "hi"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tokens, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tokens) != 2 || tokens[0] != "Hel" || tokens[1] != "lo" {
+		t.Errorf("expected [Hel lo], got %q", tokens)
+	}
+}
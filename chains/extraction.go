@@ -0,0 +1,156 @@
+package chains
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/LucaLanziani/langchain-go/core"
+	"github.com/LucaLanziani/langchain-go/llms"
+	"github.com/LucaLanziani/langchain-go/prompts"
+	"github.com/LucaLanziani/langchain-go/tools"
+)
+
+// ListExtractionChain extracts a list of typed items from its input using
+// structured output. The item schema is generated from T; the resulting
+// array schema is wrapped in an object (as required by providers that only
+// accept object schemas) and unwrapped again when parsing.
+// It implements Runnable[map[string]any, []T].
+type ListExtractionChain[T any] struct {
+	prompt *prompts.ChatPromptTemplate
+	llm    llms.ChatModel
+	schema map[string]any
+	name   string
+}
+
+// NewListExtractionChain creates a chain that extracts a []T from the
+// prompt's output.
+func NewListExtractionChain[T any](llm llms.ChatModel, prompt *prompts.ChatPromptTemplate) *ListExtractionChain[T] {
+	var zero T
+	arraySchema := map[string]any{
+		"type":  "array",
+		"items": tools.GenerateJSONSchema(zero),
+	}
+	wrapped, _ := llms.WrapArraySchema(arraySchema)
+	schema := map[string]any{
+		"name":   "extract_list",
+		"schema": wrapped,
+	}
+	return &ListExtractionChain[T]{
+		prompt: prompt,
+		llm:    llm.WithStructuredOutput(schema),
+		schema: schema,
+	}
+}
+
+// WithName sets the name for tracing.
+func (c *ListExtractionChain[T]) WithName(name string) *ListExtractionChain[T] {
+	c.name = name
+	return c
+}
+
+// GetName returns the chain name.
+func (c *ListExtractionChain[T]) GetName() string {
+	if c.name != "" {
+		return c.name
+	}
+	return "ListExtractionChain"
+}
+
+// Schema returns the structured output schema sent to the model.
+func (c *ListExtractionChain[T]) Schema() map[string]any {
+	return c.schema
+}
+
+// InputKeys returns the prompt variables that must be supplied by the caller.
+func (c *ListExtractionChain[T]) InputKeys() []string {
+	return (&LLMChain{prompt: c.prompt}).InputKeys()
+}
+
+// OutputKeys returns the output keys.
+func (c *ListExtractionChain[T]) OutputKeys() []string {
+	return []string{llms.ArrayWrapperKey}
+}
+
+// ValidateInput checks that all required prompt variables are present.
+func (c *ListExtractionChain[T]) ValidateInput(input map[string]any) error {
+	return validateInputKeys(c.GetName(), c.InputKeys(), input)
+}
+
+// Invoke runs the chain and returns the extracted items.
+func (c *ListExtractionChain[T]) Invoke(ctx context.Context, input map[string]any, opts ...core.Option) ([]T, error) {
+	if err := c.ValidateInput(input); err != nil {
+		return nil, err
+	}
+	messages, err := c.prompt.FormatMessages(input)
+	if err != nil {
+		return nil, fmt.Errorf("prompt format error: %w", err)
+	}
+
+	response, err := c.llm.Invoke(ctx, messages, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("LLM error: %w", err)
+	}
+
+	return parseListOutput[T](response.Content)
+}
+
+// Stream returns a single-chunk stream of the extracted items.
+func (c *ListExtractionChain[T]) Stream(ctx context.Context, input map[string]any, opts ...core.Option) (*core.StreamIterator[[]T], error) {
+	result, err := c.Invoke(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan core.StreamChunk[[]T], 1)
+	ch <- core.StreamChunk[[]T]{Value: result}
+	close(ch)
+	return core.NewStreamIterator(ch), nil
+}
+
+// Batch runs the chain for multiple inputs.
+func (c *ListExtractionChain[T]) Batch(ctx context.Context, inputs []map[string]any, opts ...core.Option) ([][]T, error) {
+	results := make([][]T, len(inputs))
+	for i, input := range inputs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		result, err := c.Invoke(ctx, input, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("batch item %d: %w", i, err)
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
+// parseListOutput unwraps the "items" field of the model's JSON output.
+// A bare JSON array is also accepted, for providers that do not need the
+// object wrapper.
+func parseListOutput[T any](content string) ([]T, error) {
+	content = strings.TrimSpace(content)
+	if strings.HasPrefix(content, "[") {
+		var items []T
+		if err := json.Unmarshal([]byte(content), &items); err != nil {
+			return nil, fmt.Errorf("failed to parse list output: %w", err)
+		}
+		return items, nil
+	}
+
+	var wrapper map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(content), &wrapper); err != nil {
+		return nil, fmt.Errorf("failed to parse list output: %w", err)
+	}
+	raw, ok := wrapper[llms.ArrayWrapperKey]
+	if !ok {
+		return nil, fmt.Errorf("list output is missing the %q field", llms.ArrayWrapperKey)
+	}
+	var items []T
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, fmt.Errorf("failed to parse list items: %w", err)
+	}
+	return items, nil
+}
+
+// Ensure ListExtractionChain implements Runnable.
+var _ core.Runnable[map[string]any, []string] = (*ListExtractionChain[string])(nil)
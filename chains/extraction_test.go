@@ -0,0 +1,68 @@
+package chains
+
+import (
+	"context"
+	"testing"
+
+	"github.com/LucaLanziani/langchain-go/prompts"
+)
+
+type person struct {
+	Name string `json:"name" description:"Full name"`
+	Age  int    `json:"age,omitempty"`
+}
+
+func TestListExtractionChainSchema(t *testing.T) {
+	chain := NewListExtractionChain[person](&fakeChatModel{}, prompts.NewChatPromptTemplate(prompts.Human("{text}")))
+
+	schema := chain.Schema()["schema"].(map[string]any)
+	if schema["type"] != "object" {
+		t.Fatalf("expected wrapped object schema, got %v", schema["type"])
+	}
+	items := schema["properties"].(map[string]any)["items"].(map[string]any)
+	if items["type"] != "array" {
+		t.Errorf("expected array under items, got %v", items["type"])
+	}
+	elem := items["items"].(map[string]any)
+	if _, ok := elem["properties"].(map[string]any)["name"]; !ok {
+		t.Errorf("expected item schema generated from struct, got %v", elem)
+	}
+}
+
+func TestListExtractionChainInvoke(t *testing.T) {
+	model := &fakeChatModel{response: `{"items":[{"name":"Ada","age":36},{"name":"Alan"}]}`}
+	chain := NewListExtractionChain[person](model, prompts.NewChatPromptTemplate(prompts.Human("{text}")))
+
+	people, err := chain.Invoke(context.Background(), map[string]any{"text": "Ada and Alan"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(people) != 2 || people[0].Name != "Ada" || people[0].Age != 36 || people[1].Name != "Alan" {
+		t.Errorf("unexpected result: %+v", people)
+	}
+}
+
+func TestParseListOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int
+		wantErr bool
+	}{
+		{"wrapped", `{"items":["a","b"]}`, 2, false},
+		{"bare array", ` ["a"] `, 1, false},
+		{"missing items", `{"other":[]}`, 0, true},
+		{"invalid", `not json`, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := parseListOutput[string](tt.content)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(items) != tt.want {
+				t.Errorf("expected %d items, got %d", tt.want, len(items))
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package chains
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/LucaLanziani/langchain-go/core"
+	"github.com/LucaLanziani/langchain-go/prompts"
+)
+
+// MessageContextChain passes documents to an LLM chain as individual
+// messages rather than one concatenated string. The wrapped chain's prompt
+// must contain a Placeholder for the context key ("context" by default);
+// each document becomes one message at that position.
+//
+//	prompt := prompts.NewChatPromptTemplate(
+//	    prompts.System("Answer using only the provided documents."),
+//	    prompts.Placeholder("context"),
+//	    prompts.Human("{question}"),
+//	)
+//	chain, err := chains.NewMessageContextChain(chains.NewLLMChain(model, prompt))
+type MessageContextChain struct {
+	llmChain      *LLMChain
+	contextKey    string
+	inputKey      string
+	outputKey     string
+	formatMessage func(doc *core.Document, index int) core.Message
+	name          string
+}
+
+// MessageContextOption configures a MessageContextChain.
+type MessageContextOption func(*MessageContextChain)
+
+// WithDocumentMessage sets how each document is turned into a message. The
+// index is the document's position in the input.
+func WithDocumentMessage(fn func(doc *core.Document, index int) core.Message) MessageContextOption {
+	return func(c *MessageContextChain) {
+		c.formatMessage = fn
+	}
+}
+
+// WithContextKey sets the prompt placeholder that receives the document
+// messages. Default is "context".
+func WithContextKey(key string) MessageContextOption {
+	return func(c *MessageContextChain) {
+		c.contextKey = key
+	}
+}
+
+// NewMessageContextChain creates a chain that inserts each document as its
+// own message. By default documents become human messages labelled with
+// their "source" metadata; human messages are used because some providers
+// accept only a single system prompt. It returns an error if the wrapped
+// chain's prompt has no Placeholder for the context key.
+func NewMessageContextChain(llmChain *LLMChain, opts ...MessageContextOption) (*MessageContextChain, error) {
+	c := &MessageContextChain{
+		llmChain:      llmChain,
+		contextKey:    "context",
+		inputKey:      "input_documents",
+		outputKey:     "output_text",
+		formatMessage: documentToMessage,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if !hasPlaceholder(llmChain.prompt, c.contextKey) {
+		return nil, fmt.Errorf("%s: prompt has no placeholder for context key %q", c.GetName(), c.contextKey)
+	}
+	return c, nil
+}
+
+// hasPlaceholder reports whether prompt has a Placeholder for key.
+func hasPlaceholder(prompt *prompts.ChatPromptTemplate, key string) bool {
+	for _, msg := range prompt.Messages {
+		if msg.Role == "placeholder" && msg.Template == key {
+			return true
+		}
+	}
+	return false
+}
+
+// documentToMessage renders a document as a human message, prefixed with its
+// source when one is recorded in the metadata.
+func documentToMessage(doc *core.Document, _ int) core.Message {
+	if source, ok := doc.Metadata["source"]; ok {
+		return core.NewHumanMessage(fmt.Sprintf("Source: %v\n\n%s", source, doc.PageContent))
+	}
+	return core.NewHumanMessage(doc.PageContent)
+}
+
+// GetName returns the chain name.
+func (c *MessageContextChain) GetName() string {
+	if c.name != "" {
+		return c.name
+	}
+	return "MessageContextChain"
+}
+
+// InputKeys returns the documents key plus the wrapped LLMChain's keys.
+func (c *MessageContextChain) InputKeys() []string {
+	keys := []string{c.inputKey}
+	for _, k := range c.llmChain.InputKeys() {
+		if k != c.contextKey && k != c.inputKey {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// OutputKeys returns the output keys.
+func (c *MessageContextChain) OutputKeys() []string {
+	return []string{c.outputKey}
+}
+
+// ValidateInput checks that the documents and all prompt variables are present.
+func (c *MessageContextChain) ValidateInput(input map[string]any) error {
+	return validateInputKeys(c.GetName(), c.InputKeys(), input)
+}
+
+// prepareInput returns a copy of input with the documents converted to
+// messages under the context key.
+func (c *MessageContextChain) prepareInput(input map[string]any) (map[string]any, error) {
+	if err := c.ValidateInput(input); err != nil {
+		return nil, err
+	}
+	docs, ok := input[c.inputKey].([]*core.Document)
+	if !ok {
+		return nil, fmt.Errorf("input key %q must be []*core.Document", c.inputKey)
+	}
+
+	messages := make([]core.Message, len(docs))
+	for i, doc := range docs {
+		messages[i] = c.formatMessage(doc, i)
+	}
+
+	mergedInput := make(map[string]any)
+	for k, v := range input {
+		mergedInput[k] = v
+	}
+	mergedInput[c.contextKey] = messages
+	return mergedInput, nil
+}
+
+// Invoke runs the chain with documents.
+func (c *MessageContextChain) Invoke(ctx context.Context, input map[string]any, opts ...core.Option) (string, error) {
+	cfg := core.ApplyOptions(opts...)
+	mergedInput, err := c.prepareInput(input)
+	if err != nil {
+		return "", err
+	}
+	return c.llmChain.Invoke(ctx, mergedInput, core.ChildOptions(cfg, 0, opts...)...)
+}
+
+// Stream streams the wrapped LLM chain's output.
+func (c *MessageContextChain) Stream(ctx context.Context, input map[string]any, opts ...core.Option) (*core.StreamIterator[string], error) {
+	cfg := core.ApplyOptions(opts...)
+	mergedInput, err := c.prepareInput(input)
+	if err != nil {
+		return nil, err
+	}
+	return c.llmChain.Stream(ctx, mergedInput, core.ChildOptions(cfg, 0, opts...)...)
+}
+
+// Batch runs the chain for multiple inputs.
+func (c *MessageContextChain) Batch(ctx context.Context, inputs []map[string]any, opts ...core.Option) ([]string, error) {
+	cfg := core.ApplyOptions(opts...)
+	results := make([]string, len(inputs))
+	for i, input := range inputs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		result, err := c.Invoke(ctx, input, core.ChildOptions(cfg, i, opts...)...)
+		if err != nil {
+			return nil, fmt.Errorf("batch item %d: %w", i, err)
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
+// Ensure MessageContextChain implements Chain.
+var _ Chain = (*MessageContextChain)(nil)
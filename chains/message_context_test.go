@@ -0,0 +1,105 @@
+package chains
+
+import (
+	"context"
+	"testing"
+
+	"github.com/LucaLanziani/langchain-go/core"
+	"github.com/LucaLanziani/langchain-go/prompts"
+)
+
+func messageContextPrompt() *prompts.ChatPromptTemplate {
+	return prompts.NewChatPromptTemplate(
+		prompts.System("Answer from the documents."),
+		prompts.Placeholder("context"),
+		prompts.Human("{question}"),
+	)
+}
+
+func TestMessageContextChainInvoke(t *testing.T) {
+	model := &fakeChatModel{response: "answer"}
+	chain, err := NewMessageContextChain(NewLLMChain(model, messageContextPrompt()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if keys := chain.InputKeys(); len(keys) != 2 || keys[0] != "input_documents" || keys[1] != "question" {
+		t.Errorf("expected [input_documents question], got %v", keys)
+	}
+
+	docs := []*core.Document{
+		{PageContent: "doc one", Metadata: map[string]any{"source": "a.md"}},
+		core.NewDocument("doc two"),
+	}
+	result, err := chain.Invoke(context.Background(), map[string]any{
+		"input_documents": docs,
+		"question":        "q",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != "answer" {
+		t.Errorf("expected 'answer', got %q", result)
+	}
+
+	want := []struct {
+		typ     core.MessageType
+		content string
+	}{
+		{core.MessageTypeSystem, "Answer from the documents."},
+		{core.MessageTypeHuman, "Source: a.md\n\ndoc one"},
+		{core.MessageTypeHuman, "doc two"},
+		{core.MessageTypeHuman, "q"},
+	}
+	if len(model.last) != len(want) {
+		t.Fatalf("expected %d messages, got %d", len(want), len(model.last))
+	}
+	for i, w := range want {
+		if model.last[i].GetType() != w.typ || model.last[i].GetContent() != w.content {
+			t.Errorf("message %d: expected [%s] %q, got [%s] %q",
+				i, w.typ, w.content, model.last[i].GetType(), model.last[i].GetContent())
+		}
+	}
+}
+
+func TestMessageContextChainCustomMessage(t *testing.T) {
+	model := &fakeChatModel{response: "answer"}
+	prompt := prompts.NewChatPromptTemplate(
+		prompts.Placeholder("docs"),
+		prompts.Human("{question}"),
+	)
+	chain, err := NewMessageContextChain(NewLLMChain(model, prompt),
+		WithContextKey("docs"),
+		WithDocumentMessage(func(doc *core.Document, i int) core.Message {
+			return core.NewSystemMessage(doc.PageContent)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = chain.Invoke(context.Background(), map[string]any{
+		"input_documents": []*core.Document{core.NewDocument("only doc")},
+		"question":        "q",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(model.last) != 2 || model.last[0].GetType() != core.MessageTypeSystem || model.last[0].GetContent() != "only doc" {
+		t.Errorf("unexpected messages: %v", model.last)
+	}
+
+	if _, err := chain.Invoke(context.Background(), map[string]any{"question": "q"}); err == nil {
+		t.Error("expected error for missing documents")
+	}
+}
+
+func TestMessageContextChainRequiresPlaceholder(t *testing.T) {
+	prompt := prompts.NewChatPromptTemplate(prompts.System("{context}"), prompts.Human("{question}"))
+	if _, err := NewMessageContextChain(NewLLMChain(&fakeChatModel{}, prompt)); err == nil {
+		t.Error("expected an error for a prompt without a context placeholder")
+	}
+	if _, err := NewMessageContextChain(NewLLMChain(&fakeChatModel{}, messageContextPrompt()), WithContextKey("docs")); err == nil {
+		t.Error("expected an error for a context key without a placeholder")
+	}
+}
@@ -0,0 +1,137 @@
+package core
+
+// AIMessageChunk is an incremental piece of a streamed AI message. Chunks are
+// additive: concatenating the chunks of a stream with Concat yields the
+// complete message.
+type AIMessageChunk struct {
+	AIMessage
+
+	// Index is the choice the chunk belongs to when a model generates
+	// several completions for one request. Only chunks with the same index
+	// should be concatenated.
+	Index int `json:"index,omitempty"`
+
+	// GenerationInfo holds provider metadata about the generation, such as
+	// the finish reason on the last chunk of a choice.
+	GenerationInfo map[string]any `json:"generation_info,omitempty"`
+}
+
+// NewAIMessageChunk creates a chunk holding a content delta.
+func NewAIMessageChunk(content string) *AIMessageChunk {
+	return &AIMessageChunk{AIMessage: AIMessage{BaseMessage: BaseMessage{Content: content}}}
+}
+
+// Concat returns a new chunk combining c followed by other. Content and
+// content blocks are appended, tool calls are appended, tool call chunks with
+// the same index have their arguments joined, usage is summed, and metadata
+// maps are merged with values from other taking precedence. The first
+// non-empty ID and name are kept. A nil c yields a copy of other, so a stream
+// can be folded starting from a nil chunk; a nil other is ignored.
+//
+// Concat does not check Index: the result keeps the index of c (or of other
+// when c is nil). Callers streaming several choices must group chunks by
+// Index and concatenate each group separately.
+func (c *AIMessageChunk) Concat(other *AIMessageChunk) *AIMessageChunk {
+	if other == nil {
+		other = &AIMessageChunk{}
+	}
+	if c == nil {
+		c = &AIMessageChunk{Index: other.Index}
+	}
+
+	out := &AIMessageChunk{Index: c.Index}
+	out.Content = c.Content + other.Content
+	out.ContentBlocks = appendCopy(c.ContentBlocks, other.ContentBlocks)
+	out.ID = firstNonEmpty(c.ID, other.ID)
+	out.Name = firstNonEmpty(c.Name, other.Name)
+	out.AdditionalKwargs = mergeMaps(c.AdditionalKwargs, other.AdditionalKwargs)
+	out.ResponseMetadata = mergeMaps(c.ResponseMetadata, other.ResponseMetadata)
+	out.GenerationInfo = mergeMaps(c.GenerationInfo, other.GenerationInfo)
+	out.ToolCalls = appendCopy(c.ToolCalls, other.ToolCalls)
+	out.InvalidToolCalls = appendCopy(c.InvalidToolCalls, other.InvalidToolCalls)
+	out.ToolCallChunks = mergeToolCallChunks(c.ToolCallChunks, other.ToolCallChunks)
+	out.UsageMetadata = addUsage(c.UsageMetadata, other.UsageMetadata)
+	return out
+}
+
+// Message returns the chunk as an AIMessage. If the chunk has tool call
+// chunks but no complete tool calls, the chunks are parsed into ToolCalls and
+// InvalidToolCalls.
+func (c *AIMessageChunk) Message() *AIMessage {
+	msg := c.AIMessage
+	if len(msg.ToolCalls) == 0 && len(msg.InvalidToolCalls) == 0 && len(msg.ToolCallChunks) > 0 {
+		calls := make([]ToolCall, len(msg.ToolCallChunks))
+		for i, tc := range msg.ToolCallChunks {
+			calls[i] = ToolCall{ID: tc.ID, Name: tc.Name, Args: []byte(tc.Args), Type: "function"}
+		}
+		msg.ToolCalls, msg.InvalidToolCalls = SplitToolCalls(calls)
+	}
+	return &msg
+}
+
+// mergeToolCallChunks joins chunks that share an index and appends the rest.
+func mergeToolCallChunks(a, b []ToolCallChunk) []ToolCallChunk {
+	out := appendCopy(a, nil)
+	for _, tc := range b {
+		merged := false
+		for i := range out {
+			if out[i].Index == tc.Index {
+				out[i].ID = firstNonEmpty(out[i].ID, tc.ID)
+				out[i].Name = firstNonEmpty(out[i].Name, tc.Name)
+				out[i].Args += tc.Args
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			out = append(out, tc)
+		}
+	}
+	return out
+}
+
+func addUsage(a, b *UsageMetadata) *UsageMetadata {
+	if a == nil && b == nil {
+		return nil
+	}
+	sum := &UsageMetadata{}
+	for _, u := range []*UsageMetadata{a, b} {
+		if u != nil {
+			sum.InputTokens += u.InputTokens
+			sum.OutputTokens += u.OutputTokens
+			sum.TotalTokens += u.TotalTokens
+		}
+	}
+	return sum
+}
+
+func mergeMaps(a, b map[string]any) map[string]any {
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+	out := make(map[string]any, len(a)+len(b))
+	for k, v := range a {
+		out[k] = v
+	}
+	for k, v := range b {
+		out[k] = v
+	}
+	return out
+}
+
+// appendCopy returns a new slice holding a followed by b, or nil if both
+// are empty.
+func appendCopy[T any](a, b []T) []T {
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+	out := make([]T, 0, len(a)+len(b))
+	return append(append(out, a...), b...)
+}
+
+func firstNonEmpty(a, b string) string {
+	if a != "" {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,66 @@
+package core
+
+import (
+	"testing"
+)
+
+func TestAIMessageChunkConcat(t *testing.T) {
+	var merged *AIMessageChunk
+	first := NewAIMessageChunk("Hel")
+	first.ID = "msg_1"
+	first.UsageMetadata = &UsageMetadata{InputTokens: 10, TotalTokens: 10}
+	merged = merged.Concat(first)
+	merged = merged.Concat(NewAIMessageChunk("lo"))
+
+	last := &AIMessageChunk{GenerationInfo: map[string]any{"finish_reason": "stop"}}
+	last.ToolCallChunks = []ToolCallChunk{{ID: "call_1", Name: "add", Args: `{"a"`, Index: 0}}
+	last.UsageMetadata = &UsageMetadata{OutputTokens: 2, TotalTokens: 2}
+	merged = merged.Concat(last)
+	merged = merged.Concat(&AIMessageChunk{AIMessage: AIMessage{ToolCallChunks: []ToolCallChunk{{Args: `:1}`, Index: 0}}}})
+
+	if merged.Content != "Hello" || merged.ID != "msg_1" {
+		t.Errorf("unexpected content or id: %q %q", merged.Content, merged.ID)
+	}
+	if merged.GenerationInfo["finish_reason"] != "stop" {
+		t.Errorf("expected finish reason to be kept, got %v", merged.GenerationInfo)
+	}
+	if u := merged.UsageMetadata; u == nil || u.InputTokens != 10 || u.OutputTokens != 2 || u.TotalTokens != 12 {
+		t.Errorf("expected summed usage, got %+v", u)
+	}
+	if first.Content != "Hel" || first.UsageMetadata.TotalTokens != 10 {
+		t.Errorf("Concat modified its input: %+v", first)
+	}
+
+	msg := merged.Message()
+	if len(msg.ToolCalls) != 1 || msg.ToolCalls[0].Name != "add" || string(msg.ToolCalls[0].Args) != `{"a":1}` {
+		t.Errorf("expected tool call chunks to be parsed, got %+v", msg.ToolCalls)
+	}
+}
+
+func TestAIMessageChunkConcatToolCallChunksByIndex(t *testing.T) {
+	a := &AIMessageChunk{AIMessage: AIMessage{ToolCallChunks: []ToolCallChunk{
+		{ID: "call_1", Name: "add", Args: "{", Index: 0},
+		{ID: "call_2", Name: "mul", Args: "{", Index: 1},
+	}}}
+	b := &AIMessageChunk{AIMessage: AIMessage{ToolCallChunks: []ToolCallChunk{
+		{Args: "}", Index: 1},
+		{Args: "}", Index: 0},
+	}}}
+	got := a.Concat(b).ToolCallChunks
+	if len(got) != 2 || got[0].Args != "{}" || got[1].Args != "{}" || got[1].Name != "mul" {
+		t.Errorf("unexpected tool call chunks: %+v", got)
+	}
+	if a.ToolCallChunks[0].Args != "{" {
+		t.Errorf("Concat modified its input: %+v", a.ToolCallChunks)
+	}
+}
+
+func TestAIMessageChunkConcatNil(t *testing.T) {
+	var c *AIMessageChunk
+	if got := c.Concat(nil); got == nil || got.Content != "" {
+		t.Errorf("expected an empty chunk, got %+v", got)
+	}
+	if got := NewAIMessageChunk("a").Concat(nil); got.Content != "a" {
+		t.Errorf("expected nil other to be ignored, got %q", got.Content)
+	}
+}
@@ -1,6 +1,10 @@
 package core
 
 import (
+	"maps"
+	"slices"
+	"strconv"
+
 	"github.com/google/uuid"
 )
 
@@ -32,6 +36,9 @@ type RunnableConfig struct {
 	// RunID is a unique identifier for this run. Auto-generated if empty.
 	RunID string
 
+	// ParentRunID is the RunID of the enclosing run, if any.
+	ParentRunID string
+
 	// Stop sequences to pass to the model.
 	Stop []string
 }
@@ -57,15 +64,31 @@ func ApplyOptions(opts ...Option) *RunnableConfig {
 	return cfg
 }
 
-// MergeOptions merges a base config with additional options.
+// Clone returns a copy of c whose slices and maps are independent of c's.
+// Values stored in Metadata and Configurable are copied shallowly.
+func (c *RunnableConfig) Clone() *RunnableConfig {
+	clone := *c
+	clone.Tags = slices.Clone(c.Tags)
+	clone.Metadata = maps.Clone(c.Metadata)
+	clone.Callbacks = slices.Clone(c.Callbacks)
+	clone.Configurable = maps.Clone(c.Configurable)
+	clone.Stop = slices.Clone(c.Stop)
+	return &clone
+}
+
+// MergeOptions merges a base config with additional options. The base config
+// is not modified; options are applied to a copy, which is returned.
 func MergeOptions(base *RunnableConfig, opts ...Option) *RunnableConfig {
+	var cfg *RunnableConfig
 	if base == nil {
-		base = DefaultConfig()
+		cfg = DefaultConfig()
+	} else {
+		cfg = base.Clone()
 	}
 	for _, opt := range opts {
-		opt(base)
+		opt(cfg)
 	}
-	return base
+	return cfg
 }
 
 // WithTags adds tags to the config.
@@ -122,6 +145,34 @@ func WithRunID(id string) Option {
 	}
 }
 
+// WithParentRunID sets the ID of the enclosing run.
+func WithParentRunID(id string) Option {
+	return func(c *RunnableConfig) {
+		c.ParentRunID = id
+	}
+}
+
+// runIDNamespace scopes the name-based UUIDs produced by ChildRunID.
+var runIDNamespace = uuid.NewSHA1(uuid.NameSpaceOID, []byte("langchain-go/run"))
+
+// ChildRunID derives the run ID for the step-th child of a parent run.
+// The result is a UUID that depends only on its arguments, so a caller that
+// pins the parent ID with WithRunID gets the same child IDs on every call.
+func ChildRunID(parentRunID string, step int) string {
+	return uuid.NewSHA1(runIDNamespace, []byte(parentRunID+"/"+strconv.Itoa(step))).String()
+}
+
+// ChildOptions returns opts extended so that the step-th child of parent runs
+// with a RunID derived from parent.RunID and with parent as its ParentRunID.
+func ChildOptions(parent *RunnableConfig, step int, opts ...Option) []Option {
+	child := make([]Option, 0, len(opts)+2)
+	child = append(child, opts...)
+	return append(child,
+		WithParentRunID(parent.RunID),
+		WithRunID(ChildRunID(parent.RunID, step)),
+	)
+}
+
 // WithStop sets stop sequences.
 func WithStop(stop ...string) Option {
 	return func(c *RunnableConfig) {
@@ -1,7 +1,11 @@
 package core
 
 import (
+	"fmt"
+	"sync"
 	"testing"
+
+	"github.com/google/uuid"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -70,3 +74,99 @@ func TestMergeOptions(t *testing.T) {
 		t.Errorf("expected MaxConcurrency 3, got %d", merged.MaxConcurrency)
 	}
 }
+
+func TestMergeOptionsDoesNotModifyBase(t *testing.T) {
+	base := &RunnableConfig{
+		Tags:         make([]string, 1, 4),
+		Metadata:     map[string]any{"a": 1},
+		Configurable: map[string]any{"model": "gpt-4"},
+		Stop:         []string{"\n"},
+	}
+	base.Tags[0] = "base-tag"
+
+	merged := MergeOptions(base,
+		WithTags("new-tag"),
+		WithMetadata(map[string]any{"b": 2}),
+		WithConfigurable(map[string]any{"model": "gpt-4o"}),
+		WithCallbacks(nil),
+		WithRunID("merged"),
+	)
+	merged.Stop[0] = "STOP"
+
+	if len(base.Tags) != 1 || base.Tags[:2][1] != "" {
+		t.Errorf("base tags modified: %v", base.Tags[:2])
+	}
+	if len(base.Metadata) != 1 {
+		t.Errorf("base metadata modified: %v", base.Metadata)
+	}
+	if base.Configurable["model"] != "gpt-4" {
+		t.Errorf("base configurable modified: %v", base.Configurable)
+	}
+	if len(base.Callbacks) != 0 {
+		t.Errorf("base callbacks modified: %v", base.Callbacks)
+	}
+	if base.Stop[0] != "\n" {
+		t.Errorf("base stop modified: %v", base.Stop)
+	}
+	if base.RunID != "" {
+		t.Errorf("base run ID modified: %q", base.RunID)
+	}
+	if len(merged.Tags) != 2 || merged.Metadata["b"] != 2 || merged.Configurable["model"] != "gpt-4o" {
+		t.Errorf("unexpected merged config: %+v", merged)
+	}
+}
+
+func TestMergeOptionsConcurrent(t *testing.T) {
+	base := ApplyOptions(WithTags("base"), WithMetadata(map[string]any{"a": 1}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cfg := MergeOptions(base,
+				WithTags(fmt.Sprint(i)),
+				WithMetadata(map[string]any{"i": i}),
+				WithConfigurable(map[string]any{"i": i}),
+			)
+			if cfg.Metadata["i"] != i || cfg.Tags[1] != fmt.Sprint(i) {
+				t.Errorf("merge %d: got metadata %v tags %v", i, cfg.Metadata, cfg.Tags)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if len(base.Tags) != 1 || len(base.Metadata) != 1 || base.Configurable != nil {
+		t.Errorf("base modified: %+v", base)
+	}
+}
+
+func TestChildRunID(t *testing.T) {
+	a := ChildRunID("parent", 0)
+	if a != ChildRunID("parent", 0) {
+		t.Error("expected ChildRunID to be deterministic")
+	}
+	if a == ChildRunID("parent", 1) {
+		t.Error("expected different steps to get different IDs")
+	}
+	if a == ChildRunID("other", 0) {
+		t.Error("expected different parents to get different IDs")
+	}
+	if _, err := uuid.Parse(a); err != nil {
+		t.Errorf("expected a UUID, got %q: %v", a, err)
+	}
+}
+
+func TestChildOptions(t *testing.T) {
+	parent := ApplyOptions(WithRunID("parent"), WithTags("t"))
+	cfg := ApplyOptions(ChildOptions(parent, 2, WithTags("t"))...)
+	if cfg.RunID != ChildRunID("parent", 2) {
+		t.Errorf("expected derived RunID, got %q", cfg.RunID)
+	}
+	if cfg.ParentRunID != "parent" {
+		t.Errorf("expected ParentRunID 'parent', got %q", cfg.ParentRunID)
+	}
+	if len(cfg.Tags) != 1 || cfg.Tags[0] != "t" {
+		t.Errorf("expected caller options to be kept, got tags %v", cfg.Tags)
+	}
+}
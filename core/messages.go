@@ -2,7 +2,9 @@
 package core
 
 import (
+	"bytes"
 	"encoding/json"
+	"strings"
 )
 
 // MessageType identifies the role/type of a message.
@@ -25,6 +27,41 @@ type ToolCall struct {
 	Type     string          `json:"type,omitempty"`
 }
 
+// InvalidToolCall is a tool call whose arguments could not be parsed as
+// JSON. Args holds the raw arguments as emitted by the model.
+type InvalidToolCall struct {
+	ID    string `json:"id,omitempty"`
+	Name  string `json:"name"`
+	Args  string `json:"args"`
+	Error string `json:"error"`
+}
+
+// SplitToolCalls separates tool calls with valid JSON arguments from those
+// without. Empty arguments are treated as an empty object. Providers use it
+// to fill AIMessage.ToolCalls and AIMessage.InvalidToolCalls.
+func SplitToolCalls(calls []ToolCall) ([]ToolCall, []InvalidToolCall) {
+	var valid []ToolCall
+	var invalid []InvalidToolCall
+	for _, tc := range calls {
+		if len(bytes.TrimSpace(tc.Args)) == 0 {
+			tc.Args = json.RawMessage("{}")
+		}
+		if !json.Valid(tc.Args) {
+			var v any
+			err := json.Unmarshal(tc.Args, &v)
+			invalid = append(invalid, InvalidToolCall{
+				ID:    tc.ID,
+				Name:  tc.Name,
+				Args:  string(tc.Args),
+				Error: err.Error(),
+			})
+			continue
+		}
+		valid = append(valid, tc)
+	}
+	return valid, invalid
+}
+
 // ToolCallChunk represents a streaming chunk of a tool call.
 type ToolCallChunk struct {
 	ID    string `json:"id,omitempty"`
@@ -33,8 +70,15 @@ type ToolCallChunk struct {
 	Index int    `json:"index,omitempty"`
 }
 
+// Content block types.
+const (
+	ContentBlockText  = "text"
+	ContentBlockImage = "image"
+)
+
 // ContentBlock represents a block of content within a message.
-// It can be text, an image, or other content types.
+// It can be text, an image, or other content types. An image is given
+// either by ImageURL or by raw Data with its MIMEType.
 type ContentBlock struct {
 	Type     string `json:"type"`
 	Text     string `json:"text,omitempty"`
@@ -43,6 +87,31 @@ type ContentBlock struct {
 	Data     []byte `json:"data,omitempty"`
 }
 
+// TextBlock creates a text content block.
+func TextBlock(text string) ContentBlock {
+	return ContentBlock{Type: ContentBlockText, Text: text}
+}
+
+// ImageURLBlock creates an image content block referring to a URL, which
+// may also be a data URL.
+func ImageURLBlock(url string) ContentBlock {
+	return ContentBlock{Type: ContentBlockImage, ImageURL: url}
+}
+
+// ImageDataBlock creates an image content block from raw image bytes.
+func ImageDataBlock(mimeType string, data []byte) ContentBlock {
+	return ContentBlock{Type: ContentBlockImage, MIMEType: mimeType, Data: data}
+}
+
+// GetContentBlocks returns the content blocks of msg, or nil if it has none.
+// Providers use it to serialize multimodal messages.
+func GetContentBlocks(msg Message) []ContentBlock {
+	if m, ok := msg.(interface{ GetContentBlocks() []ContentBlock }); ok {
+		return m.GetContentBlocks()
+	}
+	return nil
+}
+
 // Message is the interface all message types implement.
 type Message interface {
 	// GetType returns the message type (human, ai, system, tool, function).
@@ -62,10 +131,30 @@ type BaseMessage struct {
 	ID               string         `json:"id,omitempty"`
 	AdditionalKwargs map[string]any `json:"additional_kwargs,omitempty"`
 	ResponseMetadata map[string]any `json:"response_metadata,omitempty"`
+
+	// ContentBlocks holds structured content, such as text mixed with
+	// images. When set it takes the place of Content.
+	ContentBlocks []ContentBlock `json:"content_blocks,omitempty"`
 }
 
-// GetContent returns the text content.
-func (m *BaseMessage) GetContent() string { return m.Content }
+// GetContent returns the text content. For a message with content blocks
+// this is the concatenated text of its text blocks.
+func (m *BaseMessage) GetContent() string {
+	if len(m.ContentBlocks) == 0 {
+		return m.Content
+	}
+	var sb strings.Builder
+	for _, b := range m.ContentBlocks {
+		if b.Type == ContentBlockText {
+			sb.WriteString(b.Text)
+		}
+	}
+	return sb.String()
+}
+
+// GetContentBlocks returns the content blocks, or nil if the message has
+// only plain text content.
+func (m *BaseMessage) GetContentBlocks() []ContentBlock { return m.ContentBlocks }
 
 // GetName returns the name.
 func (m *BaseMessage) GetName() string { return m.Name }
@@ -86,12 +175,23 @@ func NewHumanMessage(content string) *HumanMessage {
 	return &HumanMessage{BaseMessage: BaseMessage{Content: content}}
 }
 
+// NewHumanMessageWithBlocks creates a HumanMessage from content blocks, for
+// example text alongside an image.
+func NewHumanMessageWithBlocks(blocks ...ContentBlock) *HumanMessage {
+	return &HumanMessage{BaseMessage: BaseMessage{ContentBlocks: blocks}}
+}
+
 // AIMessage represents a message from the AI assistant.
 type AIMessage struct {
 	BaseMessage
 	ToolCalls      []ToolCall      `json:"tool_calls,omitempty"`
 	ToolCallChunks []ToolCallChunk `json:"tool_call_chunks,omitempty"`
 	UsageMetadata  *UsageMetadata  `json:"usage_metadata,omitempty"`
+
+	// InvalidToolCalls holds tool calls whose arguments were not valid JSON.
+	// They are kept out of ToolCalls so callers can report the error back
+	// to the model.
+	InvalidToolCalls []InvalidToolCall `json:"invalid_tool_calls,omitempty"`
 }
 
 // GetType returns MessageTypeAI.
@@ -134,6 +234,11 @@ func NewSystemMessage(content string) *SystemMessage {
 type ToolMessage struct {
 	BaseMessage
 	ToolCallID string `json:"tool_call_id"`
+
+	// Artifact is structured tool output for application code, such as the
+	// typed result of a tool built with tools.NewTypedToolWithResult. It is
+	// never sent to the model.
+	Artifact any `json:"-"`
 }
 
 // GetType returns MessageTypeTool.
@@ -99,3 +99,25 @@ func TestGetBufferStringDefaults(t *testing.T) {
 		t.Errorf("expected 'Human: test', got %q", result)
 	}
 }
+
+func TestContentBlocks(t *testing.T) {
+	msg := NewHumanMessageWithBlocks(
+		TextBlock("What is in "),
+		ImageURLBlock("https://example.com/cat.png"),
+		TextBlock("this image?"),
+	)
+	if msg.GetContent() != "What is in this image?" {
+		t.Errorf("expected concatenated text, got %q", msg.GetContent())
+	}
+	if blocks := GetContentBlocks(msg); len(blocks) != 3 || blocks[1].ImageURL != "https://example.com/cat.png" {
+		t.Errorf("unexpected blocks: %+v", blocks)
+	}
+	if blocks := GetContentBlocks(NewHumanMessage("plain")); blocks != nil {
+		t.Errorf("expected no blocks for a plain message, got %+v", blocks)
+	}
+
+	result := GetBufferString([]Message{msg, NewAIMessage("A cat.")}, "", "")
+	if result != "Human: What is in this image?\nAI: A cat." {
+		t.Errorf("unexpected buffer string: %q", result)
+	}
+}
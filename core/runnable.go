@@ -91,7 +91,9 @@ func (s *StreamIterator[T]) Collect() ([]T, error) {
 	return results, nil
 }
 
-// Close signals the stream is no longer needed.
+// Close signals the stream is no longer needed. Producers observe this via
+// Done and stop sending; any chunks already in flight are drained so that
+// producers which don't select on Done are unblocked as well.
 func (s *StreamIterator[T]) Close() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -106,6 +108,29 @@ func (s *StreamIterator[T]) Close() {
 	}
 }
 
+// Done returns a channel that is closed when Close is called. Producers
+// should select on it (see Send) so they stop promptly once the consumer
+// has gone away instead of blocking on a full channel.
+func (s *StreamIterator[T]) Done() <-chan struct{} {
+	return s.done
+}
+
+// Send delivers chunk on ch unless done is closed first. It reports whether
+// the chunk was delivered; producers should stop when it returns false.
+func Send[T any](done <-chan struct{}, ch chan<- StreamChunk[T], chunk StreamChunk[T]) bool {
+	select {
+	case <-done:
+		return false
+	default:
+	}
+	select {
+	case ch <- chunk:
+		return true
+	case <-done:
+		return false
+	}
+}
+
 // StreamEvent represents an event emitted during streaming execution.
 // Used for observability and debugging.
 type StreamEvent struct {
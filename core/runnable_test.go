@@ -2,6 +2,7 @@ package core
 
 import (
 	"testing"
+	"time"
 )
 
 func TestStreamIterator(t *testing.T) {
@@ -93,3 +94,46 @@ var ErrTest = &testError{}
 type testError struct{}
 
 func (e *testError) Error() string { return "test error" }
+
+func TestStreamIteratorCloseStopsProducer(t *testing.T) {
+	for _, size := range []int{0, 1, 4} {
+		for i := 0; i < 200; i++ {
+			ch := make(chan StreamChunk[int], size)
+			iter := NewStreamIterator(ch)
+			exited := make(chan struct{})
+
+			go func() {
+				defer close(exited)
+				defer close(ch)
+				for n := 0; ; n++ {
+					if !Send(iter.Done(), ch, StreamChunk[int]{Value: n}) {
+						return
+					}
+				}
+			}()
+
+			for n := 0; n < 3; n++ {
+				if _, ok, err := iter.Next(); !ok || err != nil {
+					t.Fatalf("buffer %d: expected chunk %d, got ok=%v err=%v", size, n, ok, err)
+				}
+			}
+			iter.Close()
+
+			select {
+			case <-exited:
+			case <-time.After(time.Second):
+				t.Fatalf("buffer %d: producer did not exit after Close", size)
+			}
+		}
+	}
+}
+
+func TestSendAfterDone(t *testing.T) {
+	ch := make(chan StreamChunk[string], 1)
+	iter := NewStreamIterator(ch)
+	iter.Close()
+
+	if Send(iter.Done(), ch, StreamChunk[string]{Value: "x"}) {
+		t.Error("expected Send to report false after Close")
+	}
+}
@@ -3,6 +3,7 @@ package embeddings
 
 import (
 	"context"
+	"math"
 )
 
 // Embedder is the interface for text embedding models.
@@ -16,3 +17,21 @@ type Embedder interface {
 	// Some models distinguish between document and query embeddings.
 	EmbedQuery(ctx context.Context, text string) ([]float64, error)
 }
+
+// CosineSimilarity returns the cosine similarity of a and b. It returns 0 if
+// the vectors differ in length or either has zero norm.
+func CosineSimilarity(a, b []float64) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dotProduct, normA, normB float64
+	for i := range a {
+		dotProduct += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dotProduct / (math.Sqrt(normA) * math.Sqrt(normB))
+}
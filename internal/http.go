@@ -0,0 +1,69 @@
+package internal
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// AcceptEncoding is the Accept-Encoding header value sent by providers.
+// Setting it explicitly disables net/http's transparent gzip handling, so
+// responses must be passed through DecodeBody.
+const AcceptEncoding = "gzip, deflate"
+
+// DecodeBody returns a reader over the decompressed response body based on
+// its Content-Encoding header. Closing the returned reader closes the
+// underlying response body.
+func DecodeBody(resp *http.Response) (io.ReadCloser, error) {
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	switch encoding {
+	case "", "identity":
+		return resp.Body, nil
+	case "gzip", "x-gzip":
+		zr, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		return &decodedBody{Reader: zr, decoder: zr, body: resp.Body}, nil
+	case "deflate":
+		// "deflate" is specified as zlib-wrapped, but some servers send raw
+		// DEFLATE data; sniff the header to tell them apart.
+		br := bufio.NewReader(resp.Body)
+		if header, err := br.Peek(2); err == nil && isZlibHeader(header) {
+			zr, err := zlib.NewReader(br)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create zlib reader: %w", err)
+			}
+			return &decodedBody{Reader: zr, decoder: zr, body: resp.Body}, nil
+		}
+		fr := flate.NewReader(br)
+		return &decodedBody{Reader: fr, decoder: fr, body: resp.Body}, nil
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", encoding)
+	}
+}
+
+// decodedBody closes both the decompressor and the original body.
+type decodedBody struct {
+	io.Reader
+	decoder io.Closer
+	body    io.Closer
+}
+
+func (d *decodedBody) Close() error {
+	derr := d.decoder.Close()
+	if err := d.body.Close(); err != nil {
+		return err
+	}
+	return derr
+}
+
+// isZlibHeader reports whether b starts with a valid zlib stream header.
+func isZlibHeader(b []byte) bool {
+	return b[0]&0x0f == 8 && (uint16(b[0])<<8|uint16(b[1]))%31 == 0
+}
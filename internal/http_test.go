@@ -0,0 +1,79 @@
+package internal
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"testing"
+)
+
+// compress returns data encoded by the writer that newWriter wraps around a
+// buffer.
+func compress(t *testing.T, data string, newWriter func(io.Writer) io.WriteCloser) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := newWriter(&buf)
+	if _, err := w.Write([]byte(data)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecodeBody(t *testing.T) {
+	const payload = `{"content":"hello"}`
+	rawDeflate := func(w io.Writer) io.WriteCloser {
+		fw, err := flate.NewWriter(w, flate.DefaultCompression)
+		if err != nil {
+			t.Fatalf("flate writer: %v", err)
+		}
+		return fw
+	}
+	tests := []struct {
+		name     string
+		encoding string
+		body     []byte
+	}{
+		{"none", "", []byte(payload)},
+		{"identity", "identity", []byte(payload)},
+		{"gzip", "gzip", compress(t, payload, func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) })},
+		{"x-gzip", "X-Gzip", compress(t, payload, func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) })},
+		{"deflate zlib", "deflate", compress(t, payload, func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) })},
+		{"deflate raw", "deflate", compress(t, payload, rawDeflate)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{
+				Header: http.Header{"Content-Encoding": {tt.encoding}},
+				Body:   io.NopCloser(bytes.NewReader(tt.body)),
+			}
+			body, err := DecodeBody(resp)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer body.Close()
+			got, err := io.ReadAll(body)
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if string(got) != payload {
+				t.Errorf("expected %q, got %q", payload, got)
+			}
+		})
+	}
+}
+
+func TestDecodeBodyUnsupported(t *testing.T) {
+	resp := &http.Response{
+		Header: http.Header{"Content-Encoding": {"br"}},
+		Body:   io.NopCloser(bytes.NewReader(nil)),
+	}
+	if _, err := DecodeBody(resp); err == nil {
+		t.Error("expected an error for an unsupported encoding")
+	}
+}
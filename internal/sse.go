@@ -0,0 +1,54 @@
+package internal
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// LineReader reads newline-terminated lines from a streaming response. It
+// mirrors the bufio.Scanner API but has no maximum line length, so a single
+// large SSE data line (e.g. a big tool-call argument delta) is never
+// truncated.
+type LineReader struct {
+	r    *bufio.Reader
+	line string
+	err  error
+	eof  bool
+}
+
+// NewLineReader returns a LineReader reading from r.
+func NewLineReader(r io.Reader) *LineReader {
+	return &LineReader{r: bufio.NewReader(r)}
+}
+
+// Scan advances to the next line, which is then available through Text. It
+// returns false when the input is exhausted or a read error occurs.
+func (l *LineReader) Scan() bool {
+	if l.eof || l.err != nil {
+		return false
+	}
+	line, err := l.r.ReadString('\n')
+	if err != nil {
+		if err != io.EOF {
+			l.err = err
+			return false
+		}
+		l.eof = true
+		if line == "" {
+			return false
+		}
+	}
+	l.line = strings.TrimRight(line, "\r\n")
+	return true
+}
+
+// Text returns the most recent line read by Scan, without its line ending.
+func (l *LineReader) Text() string {
+	return l.line
+}
+
+// Err returns the first non-EOF error encountered by Scan.
+func (l *LineReader) Err() error {
+	return l.err
+}
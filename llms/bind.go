@@ -0,0 +1,83 @@
+package llms
+
+import (
+	"context"
+
+	"github.com/LucaLanziani/langchain-go/core"
+)
+
+// BoundChatModel wraps a ChatModel with options that are applied to every
+// call. Create one with Bind.
+type BoundChatModel struct {
+	model ChatModel
+	opts  []core.Option
+}
+
+// Bind returns a ChatModel that applies the given options (temperature,
+// stop sequences, model name, ...) on every call. Options passed at the call
+// site are applied after the bound ones and therefore take precedence.
+//
+//	creative := llms.Bind(base, llms.WithTemperature(1.2))
+func Bind(model ChatModel, opts ...core.Option) ChatModel {
+	if b, ok := model.(*BoundChatModel); ok {
+		merged := make([]core.Option, 0, len(b.opts)+len(opts))
+		merged = append(merged, b.opts...)
+		merged = append(merged, opts...)
+		return &BoundChatModel{model: b.model, opts: merged}
+	}
+	return &BoundChatModel{model: model, opts: opts}
+}
+
+// GetName returns the name of the wrapped model.
+func (b *BoundChatModel) GetName() string {
+	return b.model.GetName()
+}
+
+// Invoke calls the wrapped model with the bound options.
+func (b *BoundChatModel) Invoke(ctx context.Context, input []core.Message, opts ...core.Option) (*core.AIMessage, error) {
+	return b.model.Invoke(ctx, input, b.mergeOptions(opts)...)
+}
+
+// Stream streams from the wrapped model with the bound options.
+func (b *BoundChatModel) Stream(ctx context.Context, input []core.Message, opts ...core.Option) (*core.StreamIterator[*core.AIMessage], error) {
+	return b.model.Stream(ctx, input, b.mergeOptions(opts)...)
+}
+
+// StreamChunks streams chunks from the wrapped model with the bound options.
+func (b *BoundChatModel) StreamChunks(ctx context.Context, input []core.Message, opts ...core.Option) (*core.StreamIterator[*core.AIMessageChunk], error) {
+	return StreamChunks(ctx, b.model, input, b.mergeOptions(opts)...)
+}
+
+// Batch calls the wrapped model's Batch with the bound options.
+func (b *BoundChatModel) Batch(ctx context.Context, inputs [][]core.Message, opts ...core.Option) ([]*core.AIMessage, error) {
+	return b.model.Batch(ctx, inputs, b.mergeOptions(opts)...)
+}
+
+// Generate calls the wrapped model's Generate with the bound options.
+func (b *BoundChatModel) Generate(ctx context.Context, messages []core.Message, opts ...core.Option) (*ChatResult, error) {
+	return b.model.Generate(ctx, messages, b.mergeOptions(opts)...)
+}
+
+// BindTools binds tools on the wrapped model, keeping the bound options.
+func (b *BoundChatModel) BindTools(tools ...ToolDefinition) ChatModel {
+	return &BoundChatModel{model: b.model.BindTools(tools...), opts: b.opts}
+}
+
+// WithStructuredOutput configures structured output on the wrapped model,
+// keeping the bound options.
+func (b *BoundChatModel) WithStructuredOutput(schema map[string]any) ChatModel {
+	return &BoundChatModel{model: b.model.WithStructuredOutput(schema), opts: b.opts}
+}
+
+// mergeOptions returns the bound options followed by the call-site options.
+func (b *BoundChatModel) mergeOptions(opts []core.Option) []core.Option {
+	merged := make([]core.Option, 0, len(b.opts)+len(opts))
+	merged = append(merged, b.opts...)
+	return append(merged, opts...)
+}
+
+// Ensure BoundChatModel implements ChatModel.
+var _ ChatModel = (*BoundChatModel)(nil)
+
+// Ensure BoundChatModel implements ChunkStreamer.
+var _ ChunkStreamer = (*BoundChatModel)(nil)
@@ -0,0 +1,88 @@
+package llms
+
+import (
+	"context"
+	"testing"
+
+	"github.com/LucaLanziani/langchain-go/core"
+)
+
+// configModel is a test helper that records the config of its last call.
+type configModel struct {
+	last  *core.RunnableConfig
+	tools []ToolDefinition
+}
+
+func (m *configModel) GetName() string { return "configModel" }
+func (m *configModel) Invoke(_ context.Context, _ []core.Message, opts ...core.Option) (*core.AIMessage, error) {
+	m.last = core.ApplyOptions(opts...)
+	return core.NewAIMessage("ok"), nil
+}
+func (m *configModel) Stream(ctx context.Context, input []core.Message, opts ...core.Option) (*core.StreamIterator[*core.AIMessage], error) {
+	msg, _ := m.Invoke(ctx, input, opts...)
+	ch := make(chan core.StreamChunk[*core.AIMessage], 1)
+	ch <- core.StreamChunk[*core.AIMessage]{Value: msg}
+	close(ch)
+	return core.NewStreamIterator(ch), nil
+}
+func (m *configModel) Batch(ctx context.Context, inputs [][]core.Message, opts ...core.Option) ([]*core.AIMessage, error) {
+	results := make([]*core.AIMessage, len(inputs))
+	for i, in := range inputs {
+		results[i], _ = m.Invoke(ctx, in, opts...)
+	}
+	return results, nil
+}
+func (m *configModel) Generate(ctx context.Context, input []core.Message, opts ...core.Option) (*ChatResult, error) {
+	msg, _ := m.Invoke(ctx, input, opts...)
+	return &ChatResult{Generations: []*ChatGeneration{{Message: msg}}}, nil
+}
+func (m *configModel) BindTools(tools ...ToolDefinition) ChatModel {
+	cp := *m
+	cp.tools = append(cp.tools, tools...)
+	return &cp
+}
+func (m *configModel) WithStructuredOutput(map[string]any) ChatModel { return m }
+
+func TestBind(t *testing.T) {
+	base := &configModel{}
+	creative := Bind(base, WithTemperature(1.2), WithModel("gpt-4o-mini"))
+
+	if _, err := creative.Invoke(context.Background(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if base.last.Configurable[ConfigKeyTemperature] != 1.2 {
+		t.Errorf("expected bound temperature, got %v", base.last.Configurable)
+	}
+
+	// Call-site options override bound ones.
+	if _, err := creative.Invoke(context.Background(), nil, WithTemperature(0)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if base.last.Configurable[ConfigKeyTemperature] != 0.0 {
+		t.Errorf("expected call-site temperature, got %v", base.last.Configurable[ConfigKeyTemperature])
+	}
+	if base.last.Configurable[ConfigKeyModel] != "gpt-4o-mini" {
+		t.Errorf("expected bound model to be kept, got %v", base.last.Configurable[ConfigKeyModel])
+	}
+}
+
+func TestBindNestedAndBindTools(t *testing.T) {
+	base := &configModel{}
+	bound := Bind(Bind(base, WithTemperature(0.5)), core.WithStop("END"))
+
+	withTools := bound.BindTools(ToolDefinition{Name: "search"})
+	b, ok := withTools.(*BoundChatModel)
+	if !ok {
+		t.Fatalf("expected *BoundChatModel, got %T", withTools)
+	}
+	if _, err := b.Invoke(context.Background(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	inner := b.model.(*configModel)
+	if len(inner.tools) != 1 {
+		t.Errorf("expected tools on inner model, got %v", inner.tools)
+	}
+	if inner.last.Configurable[ConfigKeyTemperature] != 0.5 || len(inner.last.Stop) != 1 {
+		t.Errorf("expected bound options to survive BindTools, got %+v", inner.last)
+	}
+}
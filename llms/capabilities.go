@@ -0,0 +1,38 @@
+package llms
+
+// Capabilities describes which optional features a chat model supports.
+type Capabilities struct {
+	// ToolCalling reports whether bound tools are sent to the model and its
+	// tool calls are returned in AIMessage.ToolCalls.
+	ToolCalling bool
+
+	// StructuredOutput reports whether WithStructuredOutput is honored.
+	StructuredOutput bool
+
+	// Vision reports whether image content in messages is sent to the model.
+	Vision bool
+
+	// Streaming reports whether Stream delivers the response incrementally
+	// rather than as a single chunk.
+	Streaming bool
+}
+
+// CapabilityReporter is implemented by chat models that can describe their
+// capabilities. It is optional; use GetCapabilities to query any ChatModel.
+type CapabilityReporter interface {
+	Capabilities() Capabilities
+}
+
+// GetCapabilities returns the capabilities reported by model. The boolean is
+// false if the model does not implement CapabilityReporter, in which case
+// callers should not assume any feature is missing. Models wrapped by Bind
+// report the capabilities of the underlying model.
+func GetCapabilities(model ChatModel) (Capabilities, bool) {
+	if b, ok := model.(*BoundChatModel); ok {
+		return GetCapabilities(b.model)
+	}
+	if r, ok := model.(CapabilityReporter); ok {
+		return r.Capabilities(), true
+	}
+	return Capabilities{}, false
+}
@@ -0,0 +1,32 @@
+package llms
+
+import "testing"
+
+// capableModel is a configModel that reports capabilities.
+type capableModel struct {
+	configModel
+	caps Capabilities
+}
+
+func (m *capableModel) Capabilities() Capabilities { return m.caps }
+
+func TestGetCapabilities(t *testing.T) {
+	if _, ok := GetCapabilities(&configModel{}); ok {
+		t.Error("expected no capabilities for a model that doesn't report them")
+	}
+
+	model := &capableModel{caps: Capabilities{ToolCalling: true, Streaming: true}}
+	caps, ok := GetCapabilities(model)
+	if !ok || caps != model.caps {
+		t.Errorf("expected %+v, got %+v (ok=%v)", model.caps, caps, ok)
+	}
+
+	// Bound models report the capabilities of the model they wrap.
+	caps, ok = GetCapabilities(Bind(Bind(model, WithTemperature(0)), WithModel("m")))
+	if !ok || caps != model.caps {
+		t.Errorf("expected bound model to report %+v, got %+v (ok=%v)", model.caps, caps, ok)
+	}
+	if _, ok := GetCapabilities(Bind(&configModel{}, WithTemperature(0))); ok {
+		t.Error("expected no capabilities for a bound model that doesn't report them")
+	}
+}
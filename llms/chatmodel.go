@@ -21,7 +21,10 @@ type ChatModel interface {
 	BindTools(tools ...ToolDefinition) ChatModel
 
 	// WithStructuredOutput configures the model to return structured output
-	// matching the given JSON schema.
+	// matching the given JSON schema. The schema is passed to the provider
+	// as is: providers such as OpenAI reject a top-level array schema, so
+	// wrap one with WrapArraySchema and read the list from the output's
+	// ArrayWrapperKey field, as chains.NewListExtractionChain does.
 	WithStructuredOutput(schema map[string]any) ChatModel
 }
 
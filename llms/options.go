@@ -9,6 +9,8 @@ const (
 	ConfigKeyTopP        = "top_p"
 	ConfigKeyModel       = "model"
 	ConfigKeyResponseFmt = "response_format"
+
+	ConfigKeyParallelToolCalls = "parallel_tool_calls"
 )
 
 // WithTemperature sets the temperature for generation.
@@ -30,3 +32,9 @@ func WithTopP(p float64) core.Option {
 func WithModel(model string) core.Option {
 	return core.WithConfigurable(map[string]any{ConfigKeyModel: model})
 }
+
+// WithParallelToolCalls sets whether the model may request several tool
+// calls in one turn. Disable it when tools must run in order.
+func WithParallelToolCalls(v bool) core.Option {
+	return core.WithConfigurable(map[string]any{ConfigKeyParallelToolCalls: v})
+}
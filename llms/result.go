@@ -13,6 +13,15 @@ type ChatResult struct {
 	LLMOutput map[string]any `json:"llm_output,omitempty"`
 }
 
+// ToLLMResult converts the result to the form passed to OnLLMEnd callbacks.
+func (r *ChatResult) ToLLMResult() *core.LLMResult {
+	generations := make([]string, len(r.Generations))
+	for i, g := range r.Generations {
+		generations[i] = g.Message.Content
+	}
+	return &core.LLMResult{Generations: generations, LLMOutput: r.LLMOutput}
+}
+
 // ChatGeneration represents a single generated message.
 type ChatGeneration struct {
 	// Message is the generated AI message.
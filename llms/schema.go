@@ -0,0 +1,24 @@
+package llms
+
+// ArrayWrapperKey is the property name under which WrapArraySchema nests a
+// top-level array schema.
+const ArrayWrapperKey = "items"
+
+// WrapArraySchema wraps a top-level array schema in an object schema with a
+// single required "items" property, since providers such as OpenAI only
+// accept object schemas for structured output. Non-array schemas are
+// returned unchanged. The second return value reports whether wrapping
+// occurred.
+func WrapArraySchema(schema map[string]any) (map[string]any, bool) {
+	if schema["type"] != "array" {
+		return schema, false
+	}
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			ArrayWrapperKey: schema,
+		},
+		"required":             []string{ArrayWrapperKey},
+		"additionalProperties": false,
+	}, true
+}
@@ -0,0 +1,89 @@
+package llms
+
+import (
+	"context"
+
+	"github.com/LucaLanziani/langchain-go/core"
+)
+
+// ChunkStreamer is implemented by chat models that stream AIMessageChunk
+// values, which carry per-chunk generation info such as the finish reason
+// and the choice index. Their Stream method emits the same chunks as plain
+// AIMessages.
+type ChunkStreamer interface {
+	StreamChunks(ctx context.Context, input []core.Message, opts ...core.Option) (*core.StreamIterator[*core.AIMessageChunk], error)
+}
+
+// StreamChunks streams the model's response as AIMessageChunks. Models that
+// don't implement ChunkStreamer are streamed with Stream and each message is
+// wrapped in a chunk. Fold the chunks with AIMessageChunk.Concat to build the
+// complete message.
+func StreamChunks(ctx context.Context, model ChatModel, input []core.Message, opts ...core.Option) (*core.StreamIterator[*core.AIMessageChunk], error) {
+	if s, ok := model.(ChunkStreamer); ok {
+		return s.StreamChunks(ctx, input, opts...)
+	}
+	stream, err := model.Stream(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return mapStream(stream, func(msg *core.AIMessage) (*core.AIMessageChunk, bool) {
+		return &core.AIMessageChunk{AIMessage: *msg}, true
+	}), nil
+}
+
+// ChunksToMessages adapts a chunk stream to the message stream returned by
+// ChatModel.Stream. Providers implementing ChunkStreamer use it for Stream.
+// Chunks that carry only generation info, such as a bare finish reason, are
+// dropped, since a plain message cannot hold it. Closing the returned
+// iterator also closes chunks.
+func ChunksToMessages(chunks *core.StreamIterator[*core.AIMessageChunk]) *core.StreamIterator[*core.AIMessage] {
+	return mapStream(chunks, func(c *core.AIMessageChunk) (*core.AIMessage, bool) {
+		return &c.AIMessage, hasMessageData(c)
+	})
+}
+
+// hasMessageData reports whether c holds anything an AIMessage consumer can
+// use: content, tool calls, or usage.
+func hasMessageData(c *core.AIMessageChunk) bool {
+	return c.Content != "" || len(c.ContentBlocks) > 0 ||
+		len(c.ToolCalls) > 0 || len(c.InvalidToolCalls) > 0 || len(c.ToolCallChunks) > 0 ||
+		c.UsageMetadata != nil
+}
+
+// mapStream returns a stream of fn applied to each value of in. Values for
+// which fn reports false are skipped.
+func mapStream[T, U any](in *core.StreamIterator[T], fn func(T) (U, bool)) *core.StreamIterator[U] {
+	ch := make(chan core.StreamChunk[U], 64)
+	out := core.NewStreamIterator(ch)
+	finished := make(chan struct{})
+	go func() {
+		select {
+		case <-out.Done():
+			in.Close()
+		case <-finished:
+		}
+	}()
+	go func() {
+		defer close(ch)
+		defer close(finished)
+		defer in.Close()
+		for {
+			val, ok, err := in.Next()
+			if err != nil {
+				core.Send(out.Done(), ch, core.StreamChunk[U]{Err: err})
+				return
+			}
+			if !ok {
+				return
+			}
+			mapped, keep := fn(val)
+			if !keep {
+				continue
+			}
+			if !core.Send(out.Done(), ch, core.StreamChunk[U]{Value: mapped}) {
+				return
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,91 @@
+package llms
+
+import (
+	"context"
+	"testing"
+
+	"github.com/LucaLanziani/langchain-go/core"
+)
+
+// chunkModel streams fixed chunks through StreamChunks.
+type chunkModel struct {
+	configModel
+	chunks []*core.AIMessageChunk
+}
+
+func (m *chunkModel) StreamChunks(_ context.Context, _ []core.Message, opts ...core.Option) (*core.StreamIterator[*core.AIMessageChunk], error) {
+	m.last = core.ApplyOptions(opts...)
+	return core.NewStreamIterator(closedChunks(m.chunks)), nil
+}
+
+func TestStreamChunks(t *testing.T) {
+	stop := core.NewAIMessageChunk("")
+	stop.GenerationInfo = map[string]any{"finish_reason": "stop"}
+	model := &chunkModel{chunks: []*core.AIMessageChunk{core.NewAIMessageChunk("a"), core.NewAIMessageChunk("b"), stop}}
+
+	stream, err := StreamChunks(context.Background(), Bind(model, WithTemperature(0.5)), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	chunks, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var merged *core.AIMessageChunk
+	for _, c := range chunks {
+		merged = merged.Concat(c)
+	}
+	if merged.Content != "ab" || merged.GenerationInfo["finish_reason"] != "stop" {
+		t.Errorf("unexpected merged chunk: %+v", merged)
+	}
+	if model.last.Configurable[ConfigKeyTemperature] != 0.5 {
+		t.Errorf("expected bound options to reach the model, got %v", model.last.Configurable)
+	}
+
+	// The finish-reason chunk carries no message data and is dropped.
+	messages, err := ChunksToMessages(core.NewStreamIterator(closedChunks(chunks))).Collect()
+	if err != nil || len(messages) != 2 || messages[0].Content != "a" || messages[1].Content != "b" {
+		t.Errorf("unexpected messages: %v %v", messages, err)
+	}
+}
+
+func TestChunksToMessagesKeepsToolCallsAndUsage(t *testing.T) {
+	calls := core.NewAIMessageChunk("")
+	calls.ToolCalls = []core.ToolCall{{ID: "call_1", Name: "add", Args: []byte(`{}`)}}
+	usage := core.NewAIMessageChunk("")
+	usage.UsageMetadata = &core.UsageMetadata{TotalTokens: 3}
+	info := core.NewAIMessageChunk("")
+	info.ID = "msg_1"
+	info.GenerationInfo = map[string]any{"stop_reason": "end_turn"}
+
+	messages, err := ChunksToMessages(core.NewStreamIterator(closedChunks([]*core.AIMessageChunk{calls, info, usage}))).Collect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(messages) != 2 || len(messages[0].ToolCalls) != 1 || messages[1].UsageMetadata == nil {
+		t.Errorf("expected the tool call and usage messages only, got %+v", messages)
+	}
+}
+
+func TestStreamChunksFallsBackToStream(t *testing.T) {
+	stream, err := StreamChunks(context.Background(), &configModel{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	chunks, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(chunks) != 1 || chunks[0].Content != "ok" {
+		t.Errorf("expected the streamed message as a chunk, got %+v", chunks)
+	}
+}
+
+func closedChunks(chunks []*core.AIMessageChunk) <-chan core.StreamChunk[*core.AIMessageChunk] {
+	ch := make(chan core.StreamChunk[*core.AIMessageChunk], len(chunks))
+	for _, c := range chunks {
+		ch <- core.StreamChunk[*core.AIMessageChunk]{Value: c}
+	}
+	close(ch)
+	return ch
+}
@@ -49,6 +49,13 @@ func (h *ChatMessageHistory) GetMessages(_ context.Context) []core.Message {
 	return result
 }
 
+// SetMessages replaces the history with messages.
+func (h *ChatMessageHistory) SetMessages(_ context.Context, messages []core.Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.messages = append([]core.Message(nil), messages...)
+}
+
 // Clear removes all messages from the history.
 func (h *ChatMessageHistory) Clear(_ context.Context) {
 	h.mu.Lock()
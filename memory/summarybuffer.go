@@ -0,0 +1,216 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/LucaLanziani/langchain-go/core"
+	"github.com/LucaLanziani/langchain-go/llms"
+	"github.com/LucaLanziani/langchain-go/prompts"
+)
+
+// DefaultSummaryPrompt is the prompt used to extend the running summary. It
+// receives the current summary as {summary} and the new conversation lines
+// as {new_lines}.
+const DefaultSummaryPrompt = `Progressively summarize the lines of conversation provided, adding onto the previous summary and returning a new summary.
+
+Current summary:
+{summary}
+
+New lines of conversation:
+{new_lines}
+
+New summary:`
+
+// ConversationSummaryBufferMemory keeps recent messages verbatim and folds
+// older ones into a running summary written by an LLM. Summarization only
+// runs when the buffer grows past MaxTokenLimit; it then moves the oldest
+// messages into the summary in a single LLM call until the buffer is at most
+// PruneTokenLimit, so an active conversation is summarized every few turns
+// rather than on every save. It implements the Memory interface.
+type ConversationSummaryBufferMemory struct {
+	// ChatHistory is the backing store for the unsummarized messages.
+	ChatHistory *ChatMessageHistory
+
+	// LLM writes the summary.
+	LLM llms.ChatModel
+
+	// MaxTokenLimit is the buffer size, in tokens, above which SaveContext
+	// summarizes. Default: 2000.
+	MaxTokenLimit int
+
+	// PruneTokenLimit is the buffer size, in tokens, that summarization
+	// reduces the buffer to. Lower values mean fewer, larger summary calls.
+	// Default: half of MaxTokenLimit.
+	PruneTokenLimit int
+
+	// TokenCounter counts the tokens in a string. Default: an estimate of
+	// one token per four characters.
+	TokenCounter func(string) int
+
+	// SummaryPrompt asks the LLM for the new summary. It receives the current
+	// summary as {summary} and the new lines as {new_lines}.
+	// Default: DefaultSummaryPrompt.
+	SummaryPrompt *prompts.PromptTemplate
+
+	// MemoryKey is the key used to store/retrieve messages. Default: "history".
+	MemoryKey string
+
+	// InputKey is the key for the human input. Default: "input".
+	InputKey string
+
+	// OutputKey is the key for the AI output. Default: "output".
+	OutputKey string
+
+	// ReturnMessages controls whether to return messages or a formatted string.
+	// With messages, the summary is a leading system message.
+	ReturnMessages bool
+
+	// HumanPrefix is the prefix for human messages in string output.
+	HumanPrefix string
+
+	// AIPrefix is the prefix for AI messages in string output.
+	AIPrefix string
+
+	mu      sync.Mutex
+	summary string
+}
+
+// NewConversationSummaryBufferMemory creates a new ConversationSummaryBufferMemory
+// that summarizes with llm once the buffer exceeds maxTokenLimit tokens.
+func NewConversationSummaryBufferMemory(llm llms.ChatModel, maxTokenLimit int) *ConversationSummaryBufferMemory {
+	if maxTokenLimit <= 0 {
+		maxTokenLimit = 2000
+	}
+	return &ConversationSummaryBufferMemory{
+		ChatHistory:     NewChatMessageHistory(),
+		LLM:             llm,
+		MaxTokenLimit:   maxTokenLimit,
+		PruneTokenLimit: maxTokenLimit / 2,
+		TokenCounter:    estimateTokens,
+		SummaryPrompt:   prompts.NewPromptTemplate(DefaultSummaryPrompt),
+		MemoryKey:       "history",
+		InputKey:        "input",
+		OutputKey:       "output",
+		ReturnMessages:  false,
+		HumanPrefix:     "Human",
+		AIPrefix:        "AI",
+	}
+}
+
+// estimateTokens approximates the token count of s at four characters per token.
+func estimateTokens(s string) int {
+	return (len(s) + 3) / 4
+}
+
+// MemoryVariables returns the keys this memory produces.
+func (m *ConversationSummaryBufferMemory) MemoryVariables() []string {
+	return []string{m.MemoryKey}
+}
+
+// Summary returns the current running summary.
+func (m *ConversationSummaryBufferMemory) Summary() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.summary
+}
+
+// LoadMemoryVariables loads the summary followed by the buffered messages.
+func (m *ConversationSummaryBufferMemory) LoadMemoryVariables(ctx context.Context, _ map[string]any) (map[string]any, error) {
+	m.mu.Lock()
+	messages := m.ChatHistory.GetMessages(ctx)
+	if m.summary != "" {
+		messages = append([]core.Message{core.NewSystemMessage(m.summary)}, messages...)
+	}
+	m.mu.Unlock()
+
+	if m.ReturnMessages {
+		return map[string]any{
+			m.MemoryKey: messages,
+		}, nil
+	}
+
+	return map[string]any{
+		m.MemoryKey: core.GetBufferString(messages, m.HumanPrefix, m.AIPrefix),
+	}, nil
+}
+
+// SaveContext saves the input and output messages, summarizing the oldest
+// messages if the buffer is now over MaxTokenLimit.
+func (m *ConversationSummaryBufferMemory) SaveContext(ctx context.Context, inputs map[string]any, outputs map[string]any) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	inputVal, ok := inputs[m.InputKey]
+	if ok {
+		m.ChatHistory.AddUserMessage(ctx, toString(inputVal))
+	}
+	outputVal, ok := outputs[m.OutputKey]
+	if ok {
+		m.ChatHistory.AddAIMessage(ctx, toString(outputVal))
+	}
+
+	if m.countTokens(m.ChatHistory.GetMessages(ctx)) <= m.MaxTokenLimit {
+		return nil
+	}
+	return m.prune(ctx, m.PruneTokenLimit)
+}
+
+// Summarize folds every buffered message into the summary now, regardless
+// of the buffer size.
+func (m *ConversationSummaryBufferMemory) Summarize(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.prune(ctx, 0)
+}
+
+// Clear resets the conversation history and the summary.
+func (m *ConversationSummaryBufferMemory) Clear(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ChatHistory.Clear(ctx)
+	m.summary = ""
+	return nil
+}
+
+// prune moves the oldest turns into the summary, with one LLM call, until
+// the buffer is at most limit tokens. Whole turns are moved, so the buffer
+// never starts with the AI half of a turn. The caller must hold m.mu.
+func (m *ConversationSummaryBufferMemory) prune(ctx context.Context, limit int) error {
+	buffer := m.ChatHistory.GetMessages(ctx)
+	n := 0
+	for n < len(buffer) && m.countTokens(buffer[n:]) > limit {
+		n++
+	}
+	for n > 0 && n < len(buffer) && buffer[n].GetType() != core.MessageTypeHuman {
+		n++
+	}
+	if n == 0 {
+		return nil
+	}
+
+	prompt, err := m.SummaryPrompt.Format(map[string]any{
+		"summary":   m.summary,
+		"new_lines": core.GetBufferString(buffer[:n], m.HumanPrefix, m.AIPrefix),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to format summary prompt: %w", err)
+	}
+	response, err := m.LLM.Invoke(ctx, []core.Message{core.NewHumanMessage(prompt)})
+	if err != nil {
+		return fmt.Errorf("failed to summarize conversation: %w", err)
+	}
+
+	m.summary = response.GetContent()
+	m.ChatHistory.SetMessages(ctx, buffer[n:])
+	return nil
+}
+
+// countTokens counts the tokens of messages as rendered in string output.
+func (m *ConversationSummaryBufferMemory) countTokens(messages []core.Message) int {
+	return m.TokenCounter(core.GetBufferString(messages, m.HumanPrefix, m.AIPrefix))
+}
+
+// Ensure ConversationSummaryBufferMemory implements Memory.
+var _ Memory = (*ConversationSummaryBufferMemory)(nil)
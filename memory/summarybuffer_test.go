@@ -0,0 +1,165 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/LucaLanziani/langchain-go/core"
+	"github.com/LucaLanziani/langchain-go/llms"
+	"github.com/LucaLanziani/langchain-go/prompts"
+)
+
+// summaryModel returns "summary N" for the Nth call and records the prompts.
+type summaryModel struct {
+	prompts []string
+}
+
+func (m *summaryModel) GetName() string { return "summaryModel" }
+func (m *summaryModel) Invoke(_ context.Context, input []core.Message, _ ...core.Option) (*core.AIMessage, error) {
+	m.prompts = append(m.prompts, input[len(input)-1].GetContent())
+	return core.NewAIMessage(fmt.Sprintf("summary %d", len(m.prompts))), nil
+}
+func (m *summaryModel) Stream(ctx context.Context, input []core.Message, opts ...core.Option) (*core.StreamIterator[*core.AIMessage], error) {
+	msg, _ := m.Invoke(ctx, input, opts...)
+	ch := make(chan core.StreamChunk[*core.AIMessage], 1)
+	ch <- core.StreamChunk[*core.AIMessage]{Value: msg}
+	close(ch)
+	return core.NewStreamIterator(ch), nil
+}
+func (m *summaryModel) Batch(ctx context.Context, inputs [][]core.Message, opts ...core.Option) ([]*core.AIMessage, error) {
+	results := make([]*core.AIMessage, len(inputs))
+	for i, in := range inputs {
+		results[i], _ = m.Invoke(ctx, in, opts...)
+	}
+	return results, nil
+}
+func (m *summaryModel) Generate(ctx context.Context, input []core.Message, opts ...core.Option) (*llms.ChatResult, error) {
+	msg, _ := m.Invoke(ctx, input, opts...)
+	return &llms.ChatResult{Generations: []*llms.ChatGeneration{{Message: msg}}}, nil
+}
+func (m *summaryModel) BindTools(...llms.ToolDefinition) llms.ChatModel    { return m }
+func (m *summaryModel) WithStructuredOutput(map[string]any) llms.ChatModel { return m }
+
+// countLines is a TokenCounter that counts one token per message line.
+func countLines(s string) int {
+	if s == "" {
+		return 0
+	}
+	return strings.Count(s, "\n") + 1
+}
+
+func TestConversationSummaryBufferMemoryBatchesSummaries(t *testing.T) {
+	ctx := context.Background()
+	model := &summaryModel{}
+	mem := NewConversationSummaryBufferMemory(model, 4)
+	mem.TokenCounter = countLines
+
+	for i := 1; i <= 5; i++ {
+		err := mem.SaveContext(ctx,
+			map[string]any{"input": fmt.Sprintf("q%d", i)},
+			map[string]any{"output": fmt.Sprintf("a%d", i)},
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// Turns 3 and 5 cross the limit; each summarizes two turns at once.
+	if len(model.prompts) != 2 {
+		t.Fatalf("expected 2 summary calls, got %d", len(model.prompts))
+	}
+	if !strings.Contains(model.prompts[0], "Human: q1\nAI: a1\nHuman: q2\nAI: a2") {
+		t.Errorf("expected first call to summarize turns 1-2, got %q", model.prompts[0])
+	}
+	if !strings.Contains(model.prompts[1], "summary 1") || !strings.Contains(model.prompts[1], "Human: q4\nAI: a4") {
+		t.Errorf("expected second call to extend the summary with turns 3-4, got %q", model.prompts[1])
+	}
+
+	vars, err := mem.LoadMemoryVariables(ctx, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if vars["history"] != "System: summary 2\nHuman: q5\nAI: a5" {
+		t.Errorf("unexpected history: %q", vars["history"])
+	}
+}
+
+func TestConversationSummaryBufferMemorySummarize(t *testing.T) {
+	ctx := context.Background()
+	model := &summaryModel{}
+	mem := NewConversationSummaryBufferMemory(model, 100)
+	mem.ReturnMessages = true
+
+	_ = mem.SaveContext(ctx, map[string]any{"input": "hi"}, map[string]any{"output": "hello"})
+	if len(model.prompts) != 0 {
+		t.Fatalf("expected no summary below the limit, got %d calls", len(model.prompts))
+	}
+
+	if err := mem.Summarize(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mem.Summary() != "summary 1" {
+		t.Errorf("expected forced summary, got %q", mem.Summary())
+	}
+
+	vars, _ := mem.LoadMemoryVariables(ctx, nil)
+	messages := vars["history"].([]core.Message)
+	if len(messages) != 1 || messages[0].GetType() != core.MessageTypeSystem {
+		t.Errorf("expected only the summary message, got %v", messages)
+	}
+
+	_ = mem.Clear(ctx)
+	if mem.Summary() != "" || len(mem.ChatHistory.GetMessages(ctx)) != 0 {
+		t.Error("expected Clear to reset the summary and buffer")
+	}
+}
+
+func TestConversationSummaryBufferMemoryPrunesWholeTurns(t *testing.T) {
+	ctx := context.Background()
+	model := &summaryModel{}
+	mem := NewConversationSummaryBufferMemory(model, 4)
+	mem.TokenCounter = countLines
+	mem.PruneTokenLimit = 3
+
+	for i := 1; i <= 3; i++ {
+		_ = mem.SaveContext(ctx,
+			map[string]any{"input": fmt.Sprintf("q%d", i)},
+			map[string]any{"output": fmt.Sprintf("a%d", i)},
+		)
+	}
+
+	// Pruning to 3 lines would leave "AI: a2" at the head; the whole second
+	// turn is summarized instead.
+	if !strings.Contains(model.prompts[0], "Human: q2\nAI: a2") {
+		t.Errorf("expected turns 1-2 to be summarized, got %q", model.prompts[0])
+	}
+	messages := mem.ChatHistory.GetMessages(ctx)
+	if len(messages) != 2 || messages[0].GetType() != core.MessageTypeHuman {
+		t.Errorf("expected the buffer to start with a human message, got %v", messages)
+	}
+}
+
+func TestConversationSummaryBufferMemorySummaryPrompt(t *testing.T) {
+	ctx := context.Background()
+	model := &summaryModel{}
+	mem := NewConversationSummaryBufferMemory(model, 100)
+
+	_ = mem.SaveContext(ctx, map[string]any{"input": "are you 100% sure?"}, map[string]any{"output": "yes"})
+	if err := mem.Summarize(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(model.prompts[0], "Human: are you 100% sure?\nAI: yes") {
+		t.Errorf("expected the lines verbatim in the prompt, got %q", model.prompts[0])
+	}
+
+	mem.SummaryPrompt = prompts.NewPromptTemplate("Summary so far: {summary}\nAdd: {new_lines}")
+	_ = mem.SaveContext(ctx, map[string]any{"input": "and now?"}, map[string]any{"output": "still"})
+	if err := mem.Summarize(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "Summary so far: summary 1\nAdd: Human: and now?\nAI: still"; model.prompts[1] != want {
+		t.Errorf("expected %q, got %q", want, model.prompts[1])
+	}
+}
@@ -92,6 +92,9 @@ func (p *JSONOutputParser[T]) Stream(ctx context.Context, input *core.AIMessage,
 func (p *JSONOutputParser[T]) Batch(ctx context.Context, inputs []*core.AIMessage, opts ...core.Option) ([]T, error) {
 	results := make([]T, len(inputs))
 	for i, input := range inputs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		result, err := p.Parse(input)
 		if err != nil {
 			return nil, fmt.Errorf("batch item %d: %w", i, err)
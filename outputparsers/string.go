@@ -64,6 +64,9 @@ func (p *StringOutputParser) Stream(ctx context.Context, input *core.AIMessage,
 func (p *StringOutputParser) Batch(ctx context.Context, inputs []*core.AIMessage, opts ...core.Option) ([]string, error) {
 	results := make([]string, len(inputs))
 	for i, input := range inputs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		result, err := p.Parse(input)
 		if err != nil {
 			return nil, err
@@ -186,6 +186,9 @@ func (c *ChatPromptTemplate) Stream(ctx context.Context, input map[string]any, o
 func (c *ChatPromptTemplate) Batch(ctx context.Context, inputs []map[string]any, opts ...core.Option) ([][]core.Message, error) {
 	results := make([][]core.Message, len(inputs))
 	for i, input := range inputs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		result, err := c.FormatMessages(input)
 		if err != nil {
 			return nil, fmt.Errorf("batch item %d: %w", i, err)
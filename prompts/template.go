@@ -107,6 +107,9 @@ func (p *PromptTemplate) Stream(ctx context.Context, input map[string]any, opts
 func (p *PromptTemplate) Batch(ctx context.Context, inputs []map[string]any, opts ...core.Option) ([]string, error) {
 	results := make([]string, len(inputs))
 	for i, input := range inputs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		result, err := p.Format(input)
 		if err != nil {
 			return nil, fmt.Errorf("batch item %d: %w", i, err)
@@ -1,9 +1,9 @@
 package anthropic
 
 import (
-	"bufio"
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,7 +11,9 @@ import (
 	"os"
 	"strings"
 
+	"github.com/LucaLanziani/langchain-go/callbacks"
 	"github.com/LucaLanziani/langchain-go/core"
+	"github.com/LucaLanziani/langchain-go/internal"
 	"github.com/LucaLanziani/langchain-go/llms"
 )
 
@@ -49,6 +51,16 @@ func (m *ChatModel) GetName() string {
 	return "ChatAnthropic"
 }
 
+// Capabilities reports the features supported by the Anthropic chat model.
+// Structured output schemas are not yet sent to the API.
+func (m *ChatModel) Capabilities() llms.Capabilities {
+	return llms.Capabilities{
+		ToolCalling: true,
+		Vision:      true,
+		Streaming:   true,
+	}
+}
+
 // BindTools returns a copy of the model with tools bound.
 func (m *ChatModel) BindTools(tools ...llms.ToolDefinition) llms.ChatModel {
 	cp := *m
@@ -78,18 +90,39 @@ func (m *ChatModel) Invoke(ctx context.Context, input []core.Message, opts ...co
 // Generate performs a chat completion with full result details.
 func (m *ChatModel) Generate(ctx context.Context, messages []core.Message, opts ...core.Option) (*llms.ChatResult, error) {
 	cfg := core.ApplyOptions(opts...)
+	cbs := callbacks.NewManager(cfg.Callbacks...)
+	cbs.OnChatModelStart(ctx, messages, cfg.RunID, cfg.ParentRunID, map[string]any{"name": m.GetName()})
 	reqBody := m.buildRequest(messages, cfg, false)
 
 	respBody, err := m.doRequest(ctx, "/messages", reqBody)
 	if err != nil {
+		cbs.OnLLMError(ctx, err, cfg.RunID)
 		return nil, err
 	}
 
-	return m.parseResponse(respBody)
+	result, err := m.parseResponse(respBody)
+	if err != nil {
+		cbs.OnLLMError(ctx, err, cfg.RunID)
+		return nil, err
+	}
+	cbs.OnLLMEnd(ctx, result.ToLLMResult(), cfg.RunID)
+	return result, nil
 }
 
-// Stream sends messages and streams the response.
+// Stream sends messages and streams the response. It emits the chunks of
+// StreamChunks as AIMessages.
 func (m *ChatModel) Stream(ctx context.Context, input []core.Message, opts ...core.Option) (*core.StreamIterator[*core.AIMessage], error) {
+	chunks, err := m.StreamChunks(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return llms.ChunksToMessages(chunks), nil
+}
+
+// StreamChunks sends messages and streams the response as additive chunks.
+// The chunk sent when the message ends holds the tool calls, the output
+// token usage, and the stop reason in GenerationInfo.
+func (m *ChatModel) StreamChunks(ctx context.Context, input []core.Message, opts ...core.Option) (*core.StreamIterator[*core.AIMessageChunk], error) {
 	cfg := core.ApplyOptions(opts...)
 	reqBody := m.buildRequest(input, cfg, true)
 
@@ -98,36 +131,74 @@ func (m *ChatModel) Stream(ctx context.Context, input []core.Message, opts ...co
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.opts.BaseURL+"/messages", bytes.NewReader(reqJSON))
+	// The request context is canceled when the consumer closes the stream,
+	// which aborts any in-flight body read.
+	ch := make(chan core.StreamChunk[*core.AIMessageChunk], 64)
+	iter := core.NewStreamIterator(ch)
+	streamCtx, cancel := context.WithCancel(ctx)
+
+	req, err := http.NewRequestWithContext(streamCtx, http.MethodPost, m.opts.BaseURL+"/messages", bytes.NewReader(reqJSON))
 	if err != nil {
+		cancel()
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	m.setHeaders(req)
 
+	cbs := callbacks.NewManager(cfg.Callbacks...)
+	cbs.OnChatModelStart(ctx, input, cfg.RunID, cfg.ParentRunID, map[string]any{"name": m.GetName()})
+	fail := func(err error) (*core.StreamIterator[*core.AIMessageChunk], error) {
+		cancel()
+		cbs.OnLLMError(ctx, err, cfg.RunID)
+		return nil, err
+	}
+
 	resp, err := m.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return fail(fmt.Errorf("request failed: %w", err))
 	}
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
+	body, err := internal.DecodeBody(resp)
+	if err != nil {
 		resp.Body.Close()
-		return nil, fmt.Errorf("Anthropic API error (status %d): %s", resp.StatusCode, string(body))
+		return fail(fmt.Errorf("failed to decode response: %w", err))
+	}
+	if resp.StatusCode != http.StatusOK {
+		errBody, _ := io.ReadAll(body)
+		body.Close()
+		return fail(fmt.Errorf("Anthropic API error (status %d): %s", resp.StatusCode, string(errBody)))
 	}
 
-	ch := make(chan core.StreamChunk[*core.AIMessage], 64)
+	go func() {
+		select {
+		case <-iter.Done():
+			cancel()
+		case <-streamCtx.Done():
+		}
+	}()
 	go func() {
 		defer close(ch)
-		defer resp.Body.Close()
-		m.streamResponse(resp.Body, ch)
+		defer cancel()
+		defer body.Close()
+		merged, err := m.streamResponse(body, ch, iter.Done(), func(token string) {
+			cbs.OnLLMNewToken(ctx, token, cfg.RunID)
+		})
+		if err != nil {
+			cbs.OnLLMError(ctx, err, cfg.RunID)
+			core.Send(iter.Done(), ch, core.StreamChunk[*core.AIMessageChunk]{Err: err})
+			return
+		}
+		cbs.OnLLMEnd(ctx, &core.LLMResult{Generations: []string{merged.Content}}, cfg.RunID)
 	}()
 
-	return core.NewStreamIterator(ch), nil
+	return iter, nil
 }
 
 // Batch performs multiple chat completions.
 func (m *ChatModel) Batch(ctx context.Context, inputs [][]core.Message, opts ...core.Option) ([]*core.AIMessage, error) {
 	results := make([]*core.AIMessage, len(inputs))
 	for i, input := range inputs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		result, err := m.Invoke(ctx, input, opts...)
 		if err != nil {
 			return nil, fmt.Errorf("batch item %d: %w", i, err)
@@ -208,18 +279,37 @@ func (m *ChatModel) buildRequest(messages []core.Message, cfg *core.RunnableConf
 			}
 		}
 		req["tools"] = tools
+
+		if parallel, ok := m.parallelToolCalls(cfg); ok && !parallel {
+			req["tool_choice"] = map[string]any{
+				"type":                      "auto",
+				"disable_parallel_tool_use": true,
+			}
+		}
 	}
 
 	return req
 }
 
+// parallelToolCalls returns the parallel tool calls setting from the call
+// config or the model options, and whether one is set.
+func (m *ChatModel) parallelToolCalls(cfg *core.RunnableConfig) (bool, bool) {
+	if v, ok := cfg.Configurable[llms.ConfigKeyParallelToolCalls].(bool); ok {
+		return v, true
+	}
+	if m.opts.ParallelToolCalls != nil {
+		return *m.opts.ParallelToolCalls, true
+	}
+	return false, false
+}
+
 // messageToAPI converts a core.Message to the Anthropic API format.
 func (m *ChatModel) messageToAPI(msg core.Message) map[string]any {
 	switch msg.GetType() {
 	case core.MessageTypeHuman:
 		return map[string]any{
 			"role":    "user",
-			"content": msg.GetContent(),
+			"content": contentToAPI(msg),
 		}
 	case core.MessageTypeAI:
 		apiMsg := map[string]any{
@@ -227,10 +317,10 @@ func (m *ChatModel) messageToAPI(msg core.Message) map[string]any {
 		}
 		if ai, ok := msg.(*core.AIMessage); ok && len(ai.ToolCalls) > 0 {
 			content := []map[string]any{}
-			if ai.Content != "" {
+			if text := ai.GetContent(); text != "" {
 				content = append(content, map[string]any{
 					"type": "text",
-					"text": ai.Content,
+					"text": text,
 				})
 			}
 			for _, tc := range ai.ToolCalls {
@@ -245,7 +335,7 @@ func (m *ChatModel) messageToAPI(msg core.Message) map[string]any {
 			}
 			apiMsg["content"] = content
 		} else {
-			apiMsg["content"] = msg.GetContent()
+			apiMsg["content"] = contentToAPI(msg)
 		}
 		return apiMsg
 	case core.MessageTypeTool:
@@ -256,18 +346,62 @@ func (m *ChatModel) messageToAPI(msg core.Message) map[string]any {
 				{
 					"type":        "tool_result",
 					"tool_use_id": tm.ToolCallID,
-					"content":     tm.Content,
+					"content":     contentToAPI(tm),
 				},
 			},
 		}
 	default:
 		return map[string]any{
 			"role":    "user",
-			"content": msg.GetContent(),
+			"content": contentToAPI(msg),
 		}
 	}
 }
 
+// contentToAPI returns the message content as a string, or as an array of
+// content blocks if the message has them. Images are sent as a url source,
+// or as a base64 source for raw data and data URLs.
+func contentToAPI(msg core.Message) any {
+	blocks := core.GetContentBlocks(msg)
+	if len(blocks) == 0 {
+		return msg.GetContent()
+	}
+	content := make([]map[string]any, 0, len(blocks))
+	for _, b := range blocks {
+		switch b.Type {
+		case core.ContentBlockText:
+			content = append(content, map[string]any{"type": "text", "text": b.Text})
+		case core.ContentBlockImage:
+			content = append(content, map[string]any{"type": "image", "source": imageSource(b)})
+		}
+	}
+	return content
+}
+
+// imageSource builds the source of an image block.
+func imageSource(b core.ContentBlock) map[string]any {
+	if b.ImageURL == "" {
+		return map[string]any{
+			"type":       "base64",
+			"media_type": b.MIMEType,
+			"data":       base64.StdEncoding.EncodeToString(b.Data),
+		}
+	}
+	// data:<media type>;base64,<data>
+	if rest, ok := strings.CutPrefix(b.ImageURL, "data:"); ok {
+		if meta, data, ok := strings.Cut(rest, ","); ok {
+			if mediaType, ok := strings.CutSuffix(meta, ";base64"); ok {
+				return map[string]any{
+					"type":       "base64",
+					"media_type": mediaType,
+					"data":       data,
+				}
+			}
+		}
+	}
+	return map[string]any{"type": "url", "url": b.ImageURL}
+}
+
 // doRequest sends an HTTP request and returns the response body.
 func (m *ChatModel) doRequest(ctx context.Context, path string, body any) ([]byte, error) {
 	reqJSON, err := json.Marshal(body)
@@ -287,7 +421,13 @@ func (m *ChatModel) doRequest(ctx context.Context, path string, body any) ([]byt
 	}
 	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
+	decoded, err := internal.DecodeBody(resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	defer decoded.Close()
+
+	respBody, err := io.ReadAll(decoded)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
@@ -302,6 +442,7 @@ func (m *ChatModel) doRequest(ctx context.Context, path string, body any) ([]byt
 // setHeaders sets the standard headers for Anthropic API requests.
 func (m *ChatModel) setHeaders(req *http.Request) {
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", internal.AcceptEncoding)
 	req.Header.Set("x-api-key", m.opts.APIKey)
 	req.Header.Set("anthropic-version", anthropicAPIVersion)
 }
@@ -371,13 +512,31 @@ func (m *ChatModel) responseToMessage(resp *anthropicResponse) *core.AIMessage {
 	return core.NewAIMessage(content.String())
 }
 
-// streamResponse reads SSE events from the Anthropic streaming response.
-func (m *ChatModel) streamResponse(body io.Reader, ch chan<- core.StreamChunk[*core.AIMessage]) {
-	scanner := bufio.NewScanner(body)
-	var contentBuilder strings.Builder
+// streamResponse reads SSE events from the Anthropic streaming response and
+// sends a chunk for each text delta. The message ID and input usage are sent
+// when the message starts; tool calls are accumulated and sent with the stop
+// reason and output usage when it ends. onToken is called with each text
+// delta before it is sent. It returns the merged chunk; a read error is
+// returned rather than sent.
+func (m *ChatModel) streamResponse(body io.Reader, ch chan<- core.StreamChunk[*core.AIMessageChunk], done <-chan struct{}, onToken func(string)) (*core.AIMessageChunk, error) {
+	scanner := internal.NewLineReader(body)
+	merged := core.NewAIMessageChunk("")
 	var currentToolCall *toolCallAccumulator
 	var toolCalls []core.ToolCall
 
+	send := func(c *core.AIMessageChunk) bool {
+		merged = merged.Concat(c)
+		return core.Send(done, ch, core.StreamChunk[*core.AIMessageChunk]{Value: c})
+	}
+	// withToolCalls moves the accumulated tool calls into c.
+	withToolCalls := func(c *core.AIMessageChunk) *core.AIMessageChunk {
+		if len(toolCalls) > 0 {
+			c.ToolCalls, c.InvalidToolCalls = core.SplitToolCalls(toolCalls)
+			toolCalls = nil
+		}
+		return c
+	}
+
 	for scanner.Scan() {
 		line := scanner.Text()
 		if !strings.HasPrefix(line, "data: ") {
@@ -391,6 +550,18 @@ func (m *ChatModel) streamResponse(body io.Reader, ch chan<- core.StreamChunk[*c
 		}
 
 		switch event.Type {
+		case "message_start":
+			if event.Message != nil {
+				c := &core.AIMessageChunk{}
+				c.ID = event.Message.ID
+				if u := event.Message.Usage; u != nil {
+					c.UsageMetadata = &core.UsageMetadata{InputTokens: u.InputTokens, TotalTokens: u.InputTokens}
+				}
+				if !send(c) {
+					return merged, nil
+				}
+			}
+
 		case "content_block_start":
 			if event.ContentBlock != nil && event.ContentBlock.Type == "tool_use" {
 				currentToolCall = &toolCallAccumulator{
@@ -403,9 +574,10 @@ func (m *ChatModel) streamResponse(body io.Reader, ch chan<- core.StreamChunk[*c
 			if event.Delta != nil {
 				switch event.Delta.Type {
 				case "text_delta":
-					contentBuilder.WriteString(event.Delta.Text)
-					msg := core.NewAIMessage(event.Delta.Text)
-					ch <- core.StreamChunk[*core.AIMessage]{Value: msg}
+					onToken(event.Delta.Text)
+					if !send(core.NewAIMessageChunk(event.Delta.Text)) {
+						return merged, nil
+					}
 
 				case "input_json_delta":
 					if currentToolCall != nil {
@@ -425,13 +597,30 @@ func (m *ChatModel) streamResponse(body io.Reader, ch chan<- core.StreamChunk[*c
 				currentToolCall = nil
 			}
 
+		case "message_delta":
+			c := withToolCalls(&core.AIMessageChunk{})
+			if event.Delta != nil && event.Delta.StopReason != "" {
+				c.GenerationInfo = map[string]any{"stop_reason": event.Delta.StopReason}
+			}
+			if event.Usage != nil {
+				c.UsageMetadata = &core.UsageMetadata{OutputTokens: event.Usage.OutputTokens, TotalTokens: event.Usage.OutputTokens}
+			}
+			if !send(c) {
+				return merged, nil
+			}
+
 		case "message_stop":
 			if len(toolCalls) > 0 {
-				msg := core.NewAIMessageWithToolCalls(contentBuilder.String(), toolCalls)
-				ch <- core.StreamChunk[*core.AIMessage]{Value: msg}
+				if !send(withToolCalls(&core.AIMessageChunk{})) {
+					return merged, nil
+				}
 			}
 		}
 	}
+	if err := scanner.Err(); err != nil {
+		return merged, fmt.Errorf("failed to read stream: %w", err)
+	}
+	return merged, nil
 }
 
 type toolCallAccumulator struct {
@@ -466,17 +655,26 @@ type anthropicUsage struct {
 }
 
 type anthropicStreamEvent struct {
-	Type         string            `json:"type"`
-	ContentBlock *anthropicContent `json:"content_block,omitempty"`
-	Delta        *anthropicDelta   `json:"delta,omitempty"`
-	Index        int               `json:"index,omitempty"`
+	Type         string             `json:"type"`
+	Message      *anthropicResponse `json:"message,omitempty"`
+	ContentBlock *anthropicContent  `json:"content_block,omitempty"`
+	Delta        *anthropicDelta    `json:"delta,omitempty"`
+	Usage        *anthropicUsage    `json:"usage,omitempty"`
+	Index        int                `json:"index,omitempty"`
 }
 
 type anthropicDelta struct {
 	Type        string `json:"type,omitempty"`
 	Text        string `json:"text,omitempty"`
 	PartialJSON string `json:"partial_json,omitempty"`
+	StopReason  string `json:"stop_reason,omitempty"`
 }
 
 // Ensure ChatModel implements llms.ChatModel.
 var _ llms.ChatModel = (*ChatModel)(nil)
+
+// Ensure ChatModel implements llms.CapabilityReporter.
+var _ llms.CapabilityReporter = (*ChatModel)(nil)
+
+// Ensure ChatModel implements llms.ChunkStreamer.
+var _ llms.ChunkStreamer = (*ChatModel)(nil)
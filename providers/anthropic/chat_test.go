@@ -0,0 +1,182 @@
+package anthropic
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/LucaLanziani/langchain-go/core"
+	"github.com/LucaLanziani/langchain-go/llms"
+)
+
+// newGzipServer starts a server that replies to every request with the
+// gzip-compressed body.
+func newGzipServer(t *testing.T, contentType, body string) *httptest.Server {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(body)); err != nil {
+		t.Fatalf("gzip write: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("gzip close: %v", err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(buf.Bytes())
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestChatModel_InvokeGzip(t *testing.T) {
+	resp := `{"id":"msg_1","type":"message","role":"assistant","content":[{"type":"text","text":"hello"}],"stop_reason":"end_turn"}`
+	srv := newGzipServer(t, "application/json", resp)
+
+	model := New(WithAPIKey("test"), WithBaseURL(srv.URL))
+	msg, err := model.Invoke(context.Background(), []core.Message{core.NewHumanMessage("hi")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if msg.Content != "hello" {
+		t.Errorf("expected 'hello', got %q", msg.Content)
+	}
+}
+
+func TestChatModel_StreamGzip(t *testing.T) {
+	sse := `data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"hel"}}
+
+data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"lo"}}
+
+data: {"type":"message_stop"}
+
+`
+	srv := newGzipServer(t, "text/event-stream", sse)
+
+	model := New(WithAPIKey("test"), WithBaseURL(srv.URL))
+	stream, err := model.Stream(context.Background(), []core.Message{core.NewHumanMessage("hi")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	chunks, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var content string
+	for _, c := range chunks {
+		content += c.Content
+	}
+	if content != "hello" {
+		t.Errorf("expected 'hello', got %q", content)
+	}
+}
+
+func TestChatModel_ParallelToolCalls(t *testing.T) {
+	msgs := []core.Message{core.NewHumanMessage("hi")}
+	tool := llms.ToolDefinition{Name: "add", Parameters: map[string]any{"type": "object"}}
+
+	req := New(WithAPIKey("test")).BindTools(tool).(*ChatModel).buildRequest(msgs, core.ApplyOptions(), false)
+	if _, ok := req["tool_choice"]; ok {
+		t.Errorf("expected tool_choice to be unset, got %v", req["tool_choice"])
+	}
+
+	model := New(WithAPIKey("test"), WithParallelToolCalls(false)).BindTools(tool).(*ChatModel)
+	req = model.buildRequest(msgs, core.ApplyOptions(), false)
+	choice, _ := req["tool_choice"].(map[string]any)
+	if choice["type"] != "auto" || choice["disable_parallel_tool_use"] != true {
+		t.Errorf("expected parallel tool use to be disabled, got %v", req["tool_choice"])
+	}
+
+	// The call option overrides the model option.
+	req = model.buildRequest(msgs, core.ApplyOptions(llms.WithParallelToolCalls(true)), false)
+	if _, ok := req["tool_choice"]; ok {
+		t.Errorf("expected tool_choice to be unset, got %v", req["tool_choice"])
+	}
+}
+
+func TestChatModel_ContentBlocks(t *testing.T) {
+	msg := core.NewHumanMessageWithBlocks(
+		core.TextBlock("Compare these."),
+		core.ImageURLBlock("https://example.com/a.png"),
+		core.ImageURLBlock("data:image/jpeg;base64,QUJD"),
+		core.ImageDataBlock("image/png", []byte("ABC")),
+	)
+	req := New(WithAPIKey("test")).buildRequest([]core.Message{msg}, core.ApplyOptions(), false)
+
+	content := req["messages"].([]map[string]any)[0]["content"].([]map[string]any)
+	if len(content) != 4 {
+		t.Fatalf("expected 4 content blocks, got %v", content)
+	}
+	if content[0]["type"] != "text" || content[0]["text"] != "Compare these." {
+		t.Errorf("unexpected text block: %v", content[0])
+	}
+	if src := content[1]["source"].(map[string]any); src["type"] != "url" || src["url"] != "https://example.com/a.png" {
+		t.Errorf("unexpected url source: %v", src)
+	}
+	for _, block := range content[2:] {
+		src := block["source"].(map[string]any)
+		if block["type"] != "image" || src["type"] != "base64" || src["data"] != "QUJD" {
+			t.Errorf("unexpected base64 image block: %v", block)
+		}
+	}
+	if src := content[2]["source"].(map[string]any); src["media_type"] != "image/jpeg" {
+		t.Errorf("expected media type from the data URL, got %v", src["media_type"])
+	}
+}
+
+func TestChatModel_StreamChunks(t *testing.T) {
+	sse := `event: message_start
+data: {"type":"message_start","message":{"id":"msg_1","usage":{"input_tokens":7,"output_tokens":1}}}
+
+data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Let me "}}
+
+data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"add."}}
+
+data: {"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_1","name":"add"}}
+
+data: {"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"a\":1}"}}
+
+data: {"type":"content_block_stop","index":1}
+
+data: {"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":12}}
+
+data: {"type":"message_stop"}
+
+`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte(sse))
+	}))
+	defer srv.Close()
+	model := New(WithAPIKey("test"), WithBaseURL(srv.URL))
+
+	stream, err := model.StreamChunks(context.Background(), []core.Message{core.NewHumanMessage("hi")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	chunks, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var merged *core.AIMessageChunk
+	for _, c := range chunks {
+		merged = merged.Concat(c)
+	}
+
+	if merged.ID != "msg_1" || merged.Content != "Let me add." {
+		t.Errorf("unexpected merged chunk: %+v", merged)
+	}
+	if merged.GenerationInfo["stop_reason"] != "tool_use" {
+		t.Errorf("expected the stop reason, got %v", merged.GenerationInfo)
+	}
+	if u := merged.UsageMetadata; u == nil || u.InputTokens != 7 || u.OutputTokens != 12 || u.TotalTokens != 19 {
+		t.Errorf("unexpected usage: %+v", u)
+	}
+	if len(merged.ToolCalls) != 1 || merged.ToolCalls[0].ID != "toolu_1" || string(merged.ToolCalls[0].Args) != `{"a":1}` {
+		t.Errorf("unexpected tool calls: %+v", merged.ToolCalls)
+	}
+}
@@ -23,6 +23,10 @@ type Options struct {
 
 	// Stop sequences.
 	Stop []string
+
+	// ParallelToolCalls controls whether the model may request several tool
+	// calls in one turn. Nil leaves the provider default (enabled).
+	ParallelToolCalls *bool
 }
 
 // DefaultOptions returns sensible defaults.
@@ -56,3 +60,9 @@ func WithBaseURL(url string) OptionFunc {
 func WithMaxTokens(n int) OptionFunc {
 	return func(o *Options) { o.MaxTokens = n }
 }
+
+// WithParallelToolCalls sets whether the model may request several tool
+// calls in one turn.
+func WithParallelToolCalls(v bool) OptionFunc {
+	return func(o *Options) { o.ParallelToolCalls = &v }
+}
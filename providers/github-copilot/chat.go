@@ -11,6 +11,7 @@ import (
 
 	copilot "github.com/github/copilot-sdk/go"
 
+	"github.com/LucaLanziani/langchain-go/callbacks"
 	"github.com/LucaLanziani/langchain-go/core"
 	"github.com/LucaLanziani/langchain-go/llms"
 	"github.com/LucaLanziani/langchain-go/tools"
@@ -19,6 +20,12 @@ import (
 // Ensure ChatModel implements llms.ChatModel.
 var _ llms.ChatModel = (*ChatModel)(nil)
 
+// Ensure ChatModel implements llms.CapabilityReporter.
+var _ llms.CapabilityReporter = (*ChatModel)(nil)
+
+// Ensure ChatModel implements llms.ChunkStreamer.
+var _ llms.ChunkStreamer = (*ChatModel)(nil)
+
 // ChatModel is the GitHub Copilot chat model implementation backed by the Copilot SDK.
 type ChatModel struct {
 	opts             *Options
@@ -81,6 +88,17 @@ func (m *ChatModel) GetName() string {
 	return "ChatGitHubCopilot"
 }
 
+// Capabilities reports the features supported by the Copilot chat model.
+// The SDK runs tool calls itself rather than returning them in
+// AIMessage.ToolCalls, so ToolCalling is false; structured output is
+// requested through the system prompt.
+func (m *ChatModel) Capabilities() llms.Capabilities {
+	return llms.Capabilities{
+		StructuredOutput: true,
+		Streaming:        true,
+	}
+}
+
 // BindTools returns a copy of the model with tools bound.
 func (m *ChatModel) BindTools(toolDefs ...llms.ToolDefinition) llms.ChatModel {
 	cp := *m
@@ -110,11 +128,15 @@ func (m *ChatModel) Invoke(ctx context.Context, input []core.Message, opts ...co
 // Generate performs a chat completion with full result details.
 func (m *ChatModel) Generate(ctx context.Context, messages []core.Message, opts ...core.Option) (*llms.ChatResult, error) {
 	cfg := core.ApplyOptions(opts...)
+	cbs := callbacks.NewManager(cfg.Callbacks...)
+	cbs.OnChatModelStart(ctx, messages, cfg.RunID, cfg.ParentRunID, map[string]any{"name": m.GetName()})
 
 	sessionCfg := m.buildSessionConfig(messages, cfg)
 	session, err := m.client.CreateSession(ctx, sessionCfg)
 	if err != nil {
-		return nil, fmt.Errorf("copilot: failed to create session: %w", err)
+		err = fmt.Errorf("copilot: failed to create session: %w", err)
+		cbs.OnLLMError(ctx, err, cfg.RunID)
+		return nil, err
 	}
 	defer session.Destroy()
 
@@ -123,40 +145,97 @@ func (m *ChatModel) Generate(ctx context.Context, messages []core.Message, opts
 		Prompt: prompt,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("copilot: failed to send message: %w", err)
+		err = fmt.Errorf("copilot: failed to send message: %w", err)
+		cbs.OnLLMError(ctx, err, cfg.RunID)
+		return nil, err
 	}
 
-	return parseResponse(response), nil
+	result := parseResponse(response)
+	cbs.OnLLMEnd(ctx, result.ToLLMResult(), cfg.RunID)
+	return result, nil
 }
 
-// Stream sends messages and streams the response token by token.
+// Stream sends messages and streams the response token by token. It emits
+// the chunks of StreamChunks as AIMessages.
 func (m *ChatModel) Stream(ctx context.Context, input []core.Message, opts ...core.Option) (*core.StreamIterator[*core.AIMessage], error) {
+	chunks, err := m.StreamChunks(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return llms.ChunksToMessages(chunks), nil
+}
+
+// StreamChunks sends messages and streams the response as additive chunks.
+// The last chunk holds the token usage, when reported.
+func (m *ChatModel) StreamChunks(ctx context.Context, input []core.Message, opts ...core.Option) (*core.StreamIterator[*core.AIMessageChunk], error) {
 	cfg := core.ApplyOptions(opts...)
 
+	cbs := callbacks.NewManager(cfg.Callbacks...)
+	cbs.OnChatModelStart(ctx, input, cfg.RunID, cfg.ParentRunID, map[string]any{"name": m.GetName()})
+
 	sessionCfg := m.buildSessionConfig(input, cfg)
 	sessionCfg.Streaming = true
 
 	session, err := m.client.CreateSession(ctx, sessionCfg)
 	if err != nil {
-		return nil, fmt.Errorf("copilot: failed to create session: %w", err)
+		err = fmt.Errorf("copilot: failed to create session: %w", err)
+		cbs.OnLLMError(ctx, err, cfg.RunID)
+		return nil, err
 	}
 
-	ch := make(chan core.StreamChunk[*core.AIMessage], 64)
+	ch := make(chan core.StreamChunk[*core.AIMessageChunk], 64)
+	iter := core.NewStreamIterator(ch)
 
 	// Track whether the session has finished so we can clean up.
 	done := make(chan struct{})
 
+	// send delivers a chunk unless the stream has been finished, closed by the
+	// consumer, or canceled. The mutex keeps sends from racing with close(ch).
+	var mu sync.Mutex
+	finished := false
+	send := func(chunk core.StreamChunk[*core.AIMessageChunk]) {
+		mu.Lock()
+		defer mu.Unlock()
+		if finished {
+			return
+		}
+		select {
+		case ch <- chunk:
+		case <-iter.Done():
+		case <-ctx.Done():
+		}
+	}
+	finish := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		finished = true
+		close(ch)
+	}
+
+	// The run ends exactly once: with the response, a session error, or the
+	// stream going away first.
+	var endOnce sync.Once
+	endRun := func(result *core.LLMResult) {
+		endOnce.Do(func() { cbs.OnLLMEnd(ctx, result, cfg.RunID) })
+	}
+	failRun := func(err error) {
+		endOnce.Do(func() { cbs.OnLLMError(ctx, err, cfg.RunID) })
+	}
+
+	var content strings.Builder
 	session.On(func(event copilot.SessionEvent) {
 		switch event.Type {
 		case copilot.AssistantMessageDelta:
 			if event.Data.DeltaContent != nil {
-				msg := core.NewAIMessage(*event.Data.DeltaContent)
-				ch <- core.StreamChunk[*core.AIMessage]{Value: msg}
+				content.WriteString(*event.Data.DeltaContent)
+				cbs.OnLLMNewToken(ctx, *event.Data.DeltaContent, cfg.RunID)
+				msg := core.NewAIMessageChunk(*event.Data.DeltaContent)
+				send(core.StreamChunk[*core.AIMessageChunk]{Value: msg})
 			}
 
 		case copilot.AssistantMessage:
 			// Don't repeat content — deltas already delivered it token by token.
-			msg := core.NewAIMessage("")
+			msg := core.NewAIMessageChunk("")
 			if event.Data.InputTokens != nil || event.Data.OutputTokens != nil {
 				inputTokens := 0
 				outputTokens := 0
@@ -172,16 +251,17 @@ func (m *ChatModel) Stream(ctx context.Context, input []core.Message, opts ...co
 					TotalTokens:  inputTokens + outputTokens,
 				}
 			}
-			ch <- core.StreamChunk[*core.AIMessage]{Value: msg}
+			endRun(&core.LLMResult{Generations: []string{content.String()}})
+			send(core.StreamChunk[*core.AIMessageChunk]{Value: msg})
 
 		case copilot.SessionError:
 			errMsg := "unknown error"
 			if event.Data.Message != nil {
 				errMsg = *event.Data.Message
 			}
-			ch <- core.StreamChunk[*core.AIMessage]{
-				Err: fmt.Errorf("copilot: session error: %s", errMsg),
-			}
+			err := fmt.Errorf("copilot: session error: %s", errMsg)
+			failRun(err)
+			send(core.StreamChunk[*core.AIMessageChunk]{Err: err})
 
 		case copilot.SessionIdle:
 			close(done)
@@ -193,28 +273,34 @@ func (m *ChatModel) Stream(ctx context.Context, input []core.Message, opts ...co
 		Prompt: prompt,
 	}); err != nil {
 		session.Destroy()
-		close(ch)
-		return nil, fmt.Errorf("copilot: failed to send message: %w", err)
+		finish()
+		err = fmt.Errorf("copilot: failed to send message: %w", err)
+		cbs.OnLLMError(ctx, err, cfg.RunID)
+		return nil, err
 	}
 
-	// Clean up session when streaming is complete.
+	// Clean up session when streaming is complete or the consumer goes away.
 	go func() {
-		defer close(ch)
 		select {
 		case <-done:
 		case <-ctx.Done():
+			failRun(ctx.Err())
+		case <-iter.Done():
+			failRun(fmt.Errorf("copilot: stream closed before the response completed"))
 		}
 		session.Destroy()
+		finish()
 	}()
 
-	return core.NewStreamIterator(ch), nil
+	return iter, nil
 }
 
 // Batch performs multiple chat completions in parallel.
 // Concurrency is controlled by the MaxConcurrency option (default 5).
+// The first failure, or cancellation of ctx, stops items that have not yet
+// started and cancels those in flight.
 func (m *ChatModel) Batch(ctx context.Context, inputs [][]core.Message, opts ...core.Option) ([]*core.AIMessage, error) {
 	results := make([]*core.AIMessage, len(inputs))
-	errs := make([]error, len(inputs))
 
 	maxConc := m.opts.MaxConcurrency
 	if maxConc <= 0 {
@@ -222,17 +308,44 @@ func (m *ChatModel) Batch(ctx context.Context, inputs [][]core.Message, opts ...
 	}
 	sem := make(chan struct{}, maxConc)
 
-	var wg sync.WaitGroup
+	batchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		skipped  bool
+	)
 	for i, input := range inputs {
 		wg.Add(1)
 		go func(idx int, msgs []core.Message) {
 			defer wg.Done()
-			sem <- struct{}{}
+			skip := func() {
+				mu.Lock()
+				skipped = true
+				mu.Unlock()
+			}
+			select {
+			case sem <- struct{}{}:
+			case <-batchCtx.Done():
+				skip()
+				return
+			}
 			defer func() { <-sem }()
+			if batchCtx.Err() != nil {
+				skip()
+				return
+			}
 
-			result, err := m.Invoke(ctx, msgs, opts...)
+			result, err := m.Invoke(batchCtx, msgs, opts...)
 			if err != nil {
-				errs[idx] = fmt.Errorf("batch item %d: %w", idx, err)
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("batch item %d: %w", idx, err)
+					cancel()
+				}
+				mu.Unlock()
 				return
 			}
 			results[idx] = result
@@ -241,14 +354,20 @@ func (m *ChatModel) Batch(ctx context.Context, inputs [][]core.Message, opts ...
 
 	wg.Wait()
 
-	// Return first error encountered.
-	for _, err := range errs {
-		if err != nil {
-			return nil, err
-		}
+	// A cancellation that arrives after every item finished is not an error.
+	if firstErr == nil && !skipped {
+		return results, nil
 	}
-
-	return results, nil
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	// Items are only skipped once the batch context is canceled, which
+	// happens on an item error or a canceled ctx; never report success for
+	// a partial batch.
+	return nil, fmt.Errorf("batch stopped before all %d items ran", len(inputs))
 }
 
 // buildSessionConfig constructs the SDK SessionConfig from options and runtime config.
@@ -0,0 +1,125 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+)
+
+// Audio provides speech-to-text and text-to-speech using OpenAI's audio API.
+// It shares authentication and HTTP configuration with ChatModel.
+type Audio struct {
+	opts   *Options
+	client *http.Client
+}
+
+// NewAudio creates a new OpenAI Audio instance.
+func NewAudio(optFns ...OptionFunc) *Audio {
+	opts := DefaultOptions()
+	for _, fn := range optFns {
+		fn(opts)
+	}
+	if opts.APIKey == "" {
+		opts.APIKey = os.Getenv("OPENAI_API_KEY")
+	}
+	return &Audio{
+		opts:   opts,
+		client: defaultHTTPClient(),
+	}
+}
+
+// transcribeConfig holds per-call transcription settings.
+type transcribeConfig struct {
+	filename string
+	language string
+	prompt   string
+}
+
+// TranscribeOption configures a single Transcribe call.
+type TranscribeOption func(*transcribeConfig)
+
+// WithAudioFilename sets the filename sent with the audio. OpenAI uses its
+// extension to detect the format. Default: "audio.mp3".
+func WithAudioFilename(name string) TranscribeOption {
+	return func(c *transcribeConfig) { c.filename = name }
+}
+
+// WithAudioLanguage sets the ISO-639-1 language of the input audio.
+func WithAudioLanguage(lang string) TranscribeOption {
+	return func(c *transcribeConfig) { c.language = lang }
+}
+
+// WithAudioPrompt sets optional text to guide the transcription style.
+func WithAudioPrompt(prompt string) TranscribeOption {
+	return func(c *transcribeConfig) { c.prompt = prompt }
+}
+
+// Transcribe converts speech to text via the /audio/transcriptions endpoint.
+func (a *Audio) Transcribe(ctx context.Context, audio io.Reader, opts ...TranscribeOption) (string, error) {
+	cfg := &transcribeConfig{filename: "audio.mp3"}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	part, err := w.CreateFormFile("file", cfg.filename)
+	if err != nil {
+		return "", fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err := io.Copy(part, audio); err != nil {
+		return "", fmt.Errorf("failed to read audio: %w", err)
+	}
+	fields := map[string]string{
+		"model":           a.opts.TranscriptionModel,
+		"response_format": "json",
+		"language":        cfg.language,
+		"prompt":          cfg.prompt,
+	}
+	for k, v := range fields {
+		if v == "" {
+			continue
+		}
+		if err := w.WriteField(k, v); err != nil {
+			return "", fmt.Errorf("failed to write form field %s: %w", k, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("failed to finalize form: %w", err)
+	}
+
+	body, err := post(ctx, a.client, a.opts, "/audio/transcriptions", w.FormDataContentType(), &buf)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	var resp transcriptionResponse
+	if err := json.NewDecoder(body).Decode(&resp); err != nil {
+		return "", fmt.Errorf("failed to parse transcription response: %w", err)
+	}
+	return resp.Text, nil
+}
+
+// Speak converts text to speech via the /audio/speech endpoint. The caller
+// must close the returned audio stream.
+func (a *Audio) Speak(ctx context.Context, text, voice string) (io.ReadCloser, error) {
+	reqJSON, err := json.Marshal(map[string]any{
+		"model": a.opts.SpeechModel,
+		"input": text,
+		"voice": voice,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	return post(ctx, a.client, a.opts, "/audio/speech", "application/json", bytes.NewReader(reqJSON))
+}
+
+type transcriptionResponse struct {
+	Text string `json:"text"`
+}
@@ -0,0 +1,121 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAudio_Transcribe(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/audio/transcriptions" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer test" {
+			t.Errorf("unexpected auth header %q", got)
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Fatalf("missing file: %v", err)
+		}
+		data, _ := io.ReadAll(file)
+		if string(data) != "fake-audio" || header.Filename != "clip.wav" {
+			t.Errorf("unexpected file %q (%s)", data, header.Filename)
+		}
+		if r.FormValue("model") != "whisper-1" || r.FormValue("language") != "en" {
+			t.Errorf("unexpected form values: %v", r.MultipartForm.Value)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"text":"hello world"}`))
+	}))
+	defer srv.Close()
+
+	audio := NewAudio(WithAPIKey("test"), WithBaseURL(srv.URL))
+	text, err := audio.Transcribe(context.Background(), strings.NewReader("fake-audio"),
+		WithAudioFilename("clip.wav"), WithAudioLanguage("en"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text != "hello world" {
+		t.Errorf("expected 'hello world', got %q", text)
+	}
+}
+
+func TestAudio_Speak(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/audio/speech" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		var req map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("bad request body: %v", err)
+		}
+		if req["input"] != "hi" || req["voice"] != "alloy" || req["model"] != "tts-1" {
+			t.Errorf("unexpected request: %v", req)
+		}
+		w.Header().Set("Content-Type", "audio/mpeg")
+		w.Write([]byte("mp3-bytes"))
+	}))
+	defer srv.Close()
+
+	audio := NewAudio(WithAPIKey("test"), WithBaseURL(srv.URL))
+	stream, err := audio.Speak(context.Background(), "hi", "alloy")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer stream.Close()
+	data, err := io.ReadAll(stream)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != "mp3-bytes" {
+		t.Errorf("expected 'mp3-bytes', got %q", data)
+	}
+}
+
+func TestAudio_SpeakError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"bad voice"}`))
+	}))
+	defer srv.Close()
+
+	audio := NewAudio(WithAPIKey("test"), WithBaseURL(srv.URL))
+	if _, err := audio.Speak(context.Background(), "hi", "nope"); err == nil {
+		t.Error("expected error for non-200 response")
+	}
+}
+
+func TestAudio_ModelOptions(t *testing.T) {
+	var models []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/audio/transcriptions" {
+			models = append(models, r.FormValue("model"))
+			w.Write([]byte(`{"text":"ok"}`))
+			return
+		}
+		var req map[string]any
+		json.NewDecoder(r.Body).Decode(&req)
+		models = append(models, req["model"].(string))
+	}))
+	defer srv.Close()
+
+	audio := NewAudio(WithAPIKey("test"), WithBaseURL(srv.URL),
+		WithTranscriptionModel("gpt-4o-transcribe"), WithSpeechModel("tts-1-hd"))
+	if _, err := audio.Transcribe(context.Background(), strings.NewReader("x")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stream, err := audio.Speak(context.Background(), "hi", "alloy")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stream.Close()
+
+	if len(models) != 2 || models[0] != "gpt-4o-transcribe" || models[1] != "tts-1-hd" {
+		t.Errorf("unexpected models: %v", models)
+	}
+}
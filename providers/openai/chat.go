@@ -1,17 +1,20 @@
 package openai
 
 import (
-	"bufio"
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"sort"
 	"strings"
 
+	"github.com/LucaLanziani/langchain-go/callbacks"
 	"github.com/LucaLanziani/langchain-go/core"
+	"github.com/LucaLanziani/langchain-go/internal"
 	"github.com/LucaLanziani/langchain-go/llms"
 )
 
@@ -35,7 +38,7 @@ func New(optFns ...OptionFunc) *ChatModel {
 	}
 	return &ChatModel{
 		opts:   opts,
-		client: &http.Client{},
+		client: defaultHTTPClient(),
 	}
 }
 
@@ -47,6 +50,16 @@ func (m *ChatModel) GetName() string {
 	return "ChatOpenAI"
 }
 
+// Capabilities reports the features supported by the OpenAI chat model.
+func (m *ChatModel) Capabilities() llms.Capabilities {
+	return llms.Capabilities{
+		ToolCalling:      true,
+		StructuredOutput: true,
+		Vision:           true,
+		Streaming:        true,
+	}
+}
+
 // BindTools returns a copy of the model with tools bound.
 func (m *ChatModel) BindTools(tools ...llms.ToolDefinition) llms.ChatModel {
 	cp := *m
@@ -55,6 +68,11 @@ func (m *ChatModel) BindTools(tools ...llms.ToolDefinition) llms.ChatModel {
 }
 
 // WithStructuredOutput returns a copy of the model configured for structured output.
+// The schema is sent as the json_schema response format, for Invoke and Stream
+// alike. When streaming, each content chunk is a fragment of the JSON
+// document; concatenating the chunks yields the complete value. OpenAI only
+// accepts object schemas, and a top-level array schema is not wrapped; see
+// llms.WrapArraySchema.
 func (m *ChatModel) WithStructuredOutput(schema map[string]any) llms.ChatModel {
 	cp := *m
 	cp.structuredSchema = schema
@@ -76,18 +94,39 @@ func (m *ChatModel) Invoke(ctx context.Context, input []core.Message, opts ...co
 // Generate performs a chat completion with full result details.
 func (m *ChatModel) Generate(ctx context.Context, messages []core.Message, opts ...core.Option) (*llms.ChatResult, error) {
 	cfg := core.ApplyOptions(opts...)
+	cbs := callbacks.NewManager(cfg.Callbacks...)
+	cbs.OnChatModelStart(ctx, messages, cfg.RunID, cfg.ParentRunID, map[string]any{"name": m.GetName()})
 	reqBody := m.buildRequest(messages, cfg, false)
 
-	respBody, err := m.doRequest(ctx, "/chat/completions", reqBody)
+	respBody, err := postJSON(ctx, m.client, m.opts, "/chat/completions", reqBody)
 	if err != nil {
+		cbs.OnLLMError(ctx, err, cfg.RunID)
 		return nil, err
 	}
 
-	return m.parseResponse(respBody)
+	result, err := m.parseResponse(respBody)
+	if err != nil {
+		cbs.OnLLMError(ctx, err, cfg.RunID)
+		return nil, err
+	}
+	cbs.OnLLMEnd(ctx, result.ToLLMResult(), cfg.RunID)
+	return result, nil
 }
 
-// Stream sends messages and streams the response token by token.
+// Stream sends messages and streams the response token by token. It emits
+// the chunks of StreamChunks as AIMessages.
 func (m *ChatModel) Stream(ctx context.Context, input []core.Message, opts ...core.Option) (*core.StreamIterator[*core.AIMessage], error) {
+	chunks, err := m.StreamChunks(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return llms.ChunksToMessages(chunks), nil
+}
+
+// StreamChunks sends messages and streams the response as additive chunks.
+// Each chunk carries the index of its choice; the last chunk of a choice
+// holds its tool calls and its finish reason in GenerationInfo.
+func (m *ChatModel) StreamChunks(ctx context.Context, input []core.Message, opts ...core.Option) (*core.StreamIterator[*core.AIMessageChunk], error) {
 	cfg := core.ApplyOptions(opts...)
 	reqBody := m.buildRequest(input, cfg, true)
 
@@ -96,36 +135,58 @@ func (m *ChatModel) Stream(ctx context.Context, input []core.Message, opts ...co
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.opts.BaseURL+"/chat/completions", bytes.NewReader(reqJSON))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-	m.setHeaders(req)
+	// The request context is canceled when the consumer closes the stream,
+	// which aborts any in-flight body read.
+	ch := make(chan core.StreamChunk[*core.AIMessageChunk], 64)
+	iter := core.NewStreamIterator(ch)
+	streamCtx, cancel := context.WithCancel(ctx)
+
+	cbs := callbacks.NewManager(cfg.Callbacks...)
+	cbs.OnChatModelStart(ctx, input, cfg.RunID, cfg.ParentRunID, map[string]any{"name": m.GetName()})
 
-	resp, err := m.client.Do(req)
+	body, err := post(streamCtx, m.client, m.opts, "/chat/completions", "application/json", bytes.NewReader(reqJSON))
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		return nil, fmt.Errorf("OpenAI API error (status %d): %s", resp.StatusCode, string(body))
+		cancel()
+		cbs.OnLLMError(ctx, err, cfg.RunID)
+		return nil, err
 	}
 
-	ch := make(chan core.StreamChunk[*core.AIMessage], 64)
+	go func() {
+		select {
+		case <-iter.Done():
+			cancel()
+		case <-streamCtx.Done():
+		}
+	}()
 	go func() {
 		defer close(ch)
-		defer resp.Body.Close()
-		m.streamResponse(resp.Body, ch)
+		defer cancel()
+		defer body.Close()
+		choices, err := m.streamResponse(body, ch, iter.Done(), func(token string) {
+			cbs.OnLLMNewToken(ctx, token, cfg.RunID)
+		})
+		if err != nil {
+			cbs.OnLLMError(ctx, err, cfg.RunID)
+			core.Send(iter.Done(), ch, core.StreamChunk[*core.AIMessageChunk]{Err: err})
+			return
+		}
+		generations := make([]string, len(choices))
+		for i, c := range choices {
+			generations[i] = c.Content
+		}
+		cbs.OnLLMEnd(ctx, &core.LLMResult{Generations: generations}, cfg.RunID)
 	}()
 
-	return core.NewStreamIterator(ch), nil
+	return iter, nil
 }
 
 // Batch performs multiple chat completions.
 func (m *ChatModel) Batch(ctx context.Context, inputs [][]core.Message, opts ...core.Option) ([]*core.AIMessage, error) {
 	results := make([]*core.AIMessage, len(inputs))
 	for i, input := range inputs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		result, err := m.Invoke(ctx, input, opts...)
 		if err != nil {
 			return nil, fmt.Errorf("batch item %d: %w", i, err)
@@ -202,6 +263,10 @@ func (m *ChatModel) buildRequest(messages []core.Message, cfg *core.RunnableConf
 			}
 		}
 		req["tools"] = tools
+
+		if parallel, ok := m.parallelToolCalls(cfg); ok {
+			req["parallel_tool_calls"] = parallel
+		}
 	}
 
 	// Structured output
@@ -217,10 +282,22 @@ func (m *ChatModel) buildRequest(messages []core.Message, cfg *core.RunnableConf
 	return req
 }
 
+// parallelToolCalls returns the parallel tool calls setting from the call
+// config or the model options, and whether one is set.
+func (m *ChatModel) parallelToolCalls(cfg *core.RunnableConfig) (bool, bool) {
+	if v, ok := cfg.Configurable[llms.ConfigKeyParallelToolCalls].(bool); ok {
+		return v, true
+	}
+	if m.opts.ParallelToolCalls != nil {
+		return *m.opts.ParallelToolCalls, true
+	}
+	return false, false
+}
+
 // messageToAPI converts a core.Message to the OpenAI API format.
 func (m *ChatModel) messageToAPI(msg core.Message) map[string]any {
 	apiMsg := map[string]any{
-		"content": msg.GetContent(),
+		"content": contentToAPI(msg),
 	}
 
 	switch msg.GetType() {
@@ -265,44 +342,31 @@ func (m *ChatModel) messageToAPI(msg core.Message) map[string]any {
 	return apiMsg
 }
 
-// doRequest sends an HTTP request and returns the response body.
-func (m *ChatModel) doRequest(ctx context.Context, path string, body any) ([]byte, error) {
-	reqJSON, err := json.Marshal(body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.opts.BaseURL+path, bytes.NewReader(reqJSON))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-	m.setHeaders(req)
-
-	resp, err := m.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("OpenAI API error (status %d): %s", resp.StatusCode, string(respBody))
-	}
-
-	return respBody, nil
-}
-
-// setHeaders sets the standard headers for OpenAI API requests.
-func (m *ChatModel) setHeaders(req *http.Request) {
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+m.opts.APIKey)
-	if m.opts.Organization != "" {
-		req.Header.Set("OpenAI-Organization", m.opts.Organization)
+// contentToAPI returns the message content as a string, or as an array of
+// content parts if the message has content blocks. Images without a URL are
+// sent as base64 data URLs.
+func contentToAPI(msg core.Message) any {
+	blocks := core.GetContentBlocks(msg)
+	if len(blocks) == 0 {
+		return msg.GetContent()
+	}
+	parts := make([]map[string]any, 0, len(blocks))
+	for _, b := range blocks {
+		switch b.Type {
+		case core.ContentBlockText:
+			parts = append(parts, map[string]any{"type": "text", "text": b.Text})
+		case core.ContentBlockImage:
+			url := b.ImageURL
+			if url == "" {
+				url = "data:" + b.MIMEType + ";base64," + base64.StdEncoding.EncodeToString(b.Data)
+			}
+			parts = append(parts, map[string]any{
+				"type":      "image_url",
+				"image_url": map[string]any{"url": url},
+			})
+		}
 	}
+	return parts
 }
 
 // parseResponse parses the OpenAI chat completion response.
@@ -342,7 +406,7 @@ func (m *ChatModel) parseResponse(body []byte) (*llms.ChatResult, error) {
 					Type: "function",
 				}
 			}
-			aiMsg.ToolCalls = toolCalls
+			aiMsg.ToolCalls, aiMsg.InvalidToolCalls = core.SplitToolCalls(toolCalls)
 		}
 
 		if resp.Usage != nil {
@@ -364,11 +428,41 @@ func (m *ChatModel) parseResponse(body []byte) (*llms.ChatResult, error) {
 	return result, nil
 }
 
-// streamResponse reads SSE events from the OpenAI streaming response.
-func (m *ChatModel) streamResponse(body io.Reader, ch chan<- core.StreamChunk[*core.AIMessage]) {
-	scanner := bufio.NewScanner(body)
-	var contentBuilder strings.Builder
-	var toolCallBuilders = make(map[int]*toolCallBuilder)
+// streamResponse reads SSE events from the OpenAI streaming response and
+// sends a chunk for each content delta. Tool calls are accumulated per choice
+// and sent, with the finish reason, in a final chunk for that choice; usage
+// is sent in a chunk of its own. onToken is called with each content delta
+// before it is sent. It returns the merged chunks of the choices in index
+// order; a parse or read error is returned rather than sent.
+func (m *ChatModel) streamResponse(body io.Reader, ch chan<- core.StreamChunk[*core.AIMessageChunk], done <-chan struct{}, onToken func(string)) ([]*core.AIMessageChunk, error) {
+	scanner := internal.NewLineReader(body)
+	merged := make(map[int]*core.AIMessageChunk)
+	toolCalls := make(map[int]*toolCallAccumulator)
+
+	choices := func() []*core.AIMessageChunk {
+		indexes := make([]int, 0, len(merged))
+		for i := range merged {
+			indexes = append(indexes, i)
+		}
+		sort.Ints(indexes)
+		out := make([]*core.AIMessageChunk, len(indexes))
+		for i, index := range indexes {
+			out[i] = merged[index]
+		}
+		return out
+	}
+	send := func(c *core.AIMessageChunk) bool {
+		merged[c.Index] = merged[c.Index].Concat(c)
+		return core.Send(done, ch, core.StreamChunk[*core.AIMessageChunk]{Value: c})
+	}
+	// finish sends the accumulated tool calls of a choice with info.
+	finish := func(c *core.AIMessageChunk) bool {
+		if acc := toolCalls[c.Index]; acc != nil {
+			c.ToolCalls, c.InvalidToolCalls = core.SplitToolCalls(acc.toolCalls())
+			delete(toolCalls, c.Index)
+		}
+		return send(c)
+	}
 
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -380,54 +474,75 @@ func (m *ChatModel) streamResponse(body io.Reader, ch chan<- core.StreamChunk[*c
 			break
 		}
 
-		var chunk openAIStreamChunk
-		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
-			ch <- core.StreamChunk[*core.AIMessage]{Err: fmt.Errorf("failed to parse stream chunk: %w", err)}
-			return
+		var event openAIStreamChunk
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return choices(), fmt.Errorf("failed to parse stream chunk: %w", err)
 		}
 
-		for _, choice := range chunk.Choices {
+		for _, choice := range event.Choices {
 			delta := choice.Delta
 
 			// Content delta
 			if delta.Content != "" {
-				contentBuilder.WriteString(delta.Content)
-				msg := core.NewAIMessage(delta.Content)
-				ch <- core.StreamChunk[*core.AIMessage]{Value: msg}
+				onToken(delta.Content)
+				c := core.NewAIMessageChunk(delta.Content)
+				c.ID = event.ID
+				c.Index = choice.Index
+				if !send(c) {
+					return choices(), nil
+				}
 			}
 
 			// Tool call deltas
 			for _, tc := range delta.ToolCalls {
-				builder, ok := toolCallBuilders[tc.Index]
-				if !ok {
-					builder = &toolCallBuilder{}
-					toolCallBuilders[tc.Index] = builder
+				if toolCalls[choice.Index] == nil {
+					toolCalls[choice.Index] = &toolCallAccumulator{}
 				}
-				if tc.ID != "" {
-					builder.id = tc.ID
+				toolCalls[choice.Index].add(tc)
+			}
+
+			if choice.FinishReason != nil && *choice.FinishReason != "" {
+				c := &core.AIMessageChunk{
+					Index:          choice.Index,
+					GenerationInfo: map[string]any{"finish_reason": *choice.FinishReason},
 				}
-				if tc.Function.Name != "" {
-					builder.name = tc.Function.Name
+				c.ID = event.ID
+				if !finish(c) {
+					return choices(), nil
 				}
-				builder.args += tc.Function.Arguments
+			}
+		}
+
+		if event.Usage != nil {
+			c := &core.AIMessageChunk{}
+			c.ID = event.ID
+			c.UsageMetadata = &core.UsageMetadata{
+				InputTokens:  event.Usage.PromptTokens,
+				OutputTokens: event.Usage.CompletionTokens,
+				TotalTokens:  event.Usage.TotalTokens,
+			}
+			if !send(c) {
+				return choices(), nil
 			}
 		}
 	}
 
-	// If we accumulated tool calls, send a final message with them.
-	if len(toolCallBuilders) > 0 {
-		var toolCalls []core.ToolCall
-		for _, builder := range toolCallBuilders {
-			toolCalls = append(toolCalls, core.ToolCall{
-				ID:   builder.id,
-				Name: builder.name,
-				Args: json.RawMessage(builder.args),
-				Type: "function",
-			})
+	if err := scanner.Err(); err != nil {
+		return choices(), fmt.Errorf("failed to read stream: %w", err)
+	}
+
+	// Send tool calls of choices that ended without a finish reason.
+	pending := make([]int, 0, len(toolCalls))
+	for index := range toolCalls {
+		pending = append(pending, index)
+	}
+	sort.Ints(pending)
+	for _, index := range pending {
+		if !finish(&core.AIMessageChunk{Index: index}) {
+			break
 		}
-		msg := core.NewAIMessageWithToolCalls(contentBuilder.String(), toolCalls)
-		ch <- core.StreamChunk[*core.AIMessage]{Value: msg}
 	}
+	return choices(), nil
 }
 
 type toolCallBuilder struct {
@@ -436,6 +551,89 @@ type toolCallBuilder struct {
 	args string
 }
 
+// toolCallAccumulator assembles streamed tool-call deltas into complete calls.
+//
+// The reference API sends an index on every delta, but some compatible
+// servers omit it on continuation deltas, reuse index 0 for every call, or
+// interleave calls. Deltas are therefore matched by id first, then by index,
+// and finally fall back to the most recently touched call. A delta carrying
+// an id that hasn't been seen before always starts a new call.
+type toolCallAccumulator struct {
+	calls   []*toolCallBuilder
+	byID    map[string]*toolCallBuilder
+	byIndex map[int]*toolCallBuilder
+	current *toolCallBuilder
+}
+
+func (a *toolCallAccumulator) add(tc openAIStreamToolCall) {
+	if a.byID == nil {
+		a.byID = make(map[string]*toolCallBuilder)
+		a.byIndex = make(map[int]*toolCallBuilder)
+	}
+
+	var builder *toolCallBuilder
+	switch {
+	case tc.ID != "":
+		builder = a.byID[tc.ID]
+		if builder == nil {
+			// An indexed call that hasn't received its id yet keeps it.
+			if b := a.lookupIndex(tc.Index); b != nil && b.id == "" {
+				builder = b
+			} else {
+				builder = a.start()
+			}
+			builder.id = tc.ID
+			a.byID[tc.ID] = builder
+		}
+	case tc.Index != nil:
+		builder = a.byIndex[*tc.Index]
+		if builder == nil {
+			builder = a.start()
+		}
+	default:
+		builder = a.current
+		if builder == nil {
+			builder = a.start()
+		}
+	}
+	if tc.Index != nil {
+		a.byIndex[*tc.Index] = builder
+	}
+	a.current = builder
+
+	if tc.Function.Name != "" {
+		builder.name = tc.Function.Name
+	}
+	builder.args += tc.Function.Arguments
+}
+
+func (a *toolCallAccumulator) lookupIndex(index *int) *toolCallBuilder {
+	if index == nil {
+		return nil
+	}
+	return a.byIndex[*index]
+}
+
+func (a *toolCallAccumulator) start() *toolCallBuilder {
+	b := &toolCallBuilder{}
+	a.calls = append(a.calls, b)
+	return b
+}
+
+// toolCalls returns the accumulated calls in the order they were started.
+func (a *toolCallAccumulator) toolCalls() []core.ToolCall {
+	toolCalls := make([]core.ToolCall, len(a.calls))
+	for i, b := range a.calls {
+		toolCalls[i] = core.ToolCall{
+			ID:   b.id,
+			Name: b.name,
+			Args: json.RawMessage(b.args),
+			Type: "function",
+		}
+	}
+	return toolCalls
+}
+
 // OpenAI API types
 
 type openAIChatResponse struct {
@@ -498,7 +696,7 @@ type openAIStreamDelta struct {
 }
 
 type openAIStreamToolCall struct {
-	Index    int                `json:"index"`
+	Index    *int               `json:"index,omitempty"`
 	ID       string             `json:"id,omitempty"`
 	Type     string             `json:"type,omitempty"`
 	Function openAIFunctionCall `json:"function"`
@@ -506,3 +704,9 @@ type openAIStreamToolCall struct {
 
 // Ensure ChatModel implements llms.ChatModel.
 var _ llms.ChatModel = (*ChatModel)(nil)
+
+// Ensure ChatModel implements llms.CapabilityReporter.
+var _ llms.CapabilityReporter = (*ChatModel)(nil)
+
+// Ensure ChatModel implements llms.ChunkStreamer.
+var _ llms.ChunkStreamer = (*ChatModel)(nil)
@@ -0,0 +1,480 @@
+package openai
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LucaLanziani/langchain-go/callbacks"
+	"github.com/LucaLanziani/langchain-go/core"
+	"github.com/LucaLanziani/langchain-go/llms"
+)
+
+// newTestServer starts a server that replies to every request with the given
+// body and headers.
+func newTestServer(t *testing.T, contentType string, body []byte, headers map[string]string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		for k, v := range headers {
+			w.Header().Set(k, v)
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func gzipBytes(t *testing.T, data string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(data)); err != nil {
+		t.Fatalf("gzip write: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("gzip close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestChatModel_InvokeGzip(t *testing.T) {
+	resp := `{"id":"1","model":"gpt-4o","choices":[{"index":0,"message":{"role":"assistant","content":"hello"},"finish_reason":"stop"}]}`
+	srv := newTestServer(t, "application/json", gzipBytes(t, resp), map[string]string{"Content-Encoding": "gzip"})
+
+	model := New(WithAPIKey("test"), WithBaseURL(srv.URL))
+	msg, err := model.Invoke(context.Background(), []core.Message{core.NewHumanMessage("hi")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if msg.Content != "hello" {
+		t.Errorf("expected 'hello', got %q", msg.Content)
+	}
+}
+
+func TestChatModel_StreamGzip(t *testing.T) {
+	sse := "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"hel\"}}]}\n\n" +
+		"data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"lo\"}}]}\n\n" +
+		"data: [DONE]\n\n"
+	srv := newTestServer(t, "text/event-stream", gzipBytes(t, sse), map[string]string{"Content-Encoding": "gzip"})
+
+	model := New(WithAPIKey("test"), WithBaseURL(srv.URL))
+	stream, err := model.Stream(context.Background(), []core.Message{core.NewHumanMessage("hi")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	chunks, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var content string
+	for _, c := range chunks {
+		content += c.Content
+	}
+	if content != "hello" {
+		t.Errorf("expected 'hello', got %q", content)
+	}
+}
+
+func TestChatModel_StreamCloseCancelsRequest(t *testing.T) {
+	canceled := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"hi\"}}]}\n\n"))
+		w.(http.Flusher).Flush()
+		// Hold the connection open until the client goes away.
+		<-r.Context().Done()
+		close(canceled)
+	}))
+	t.Cleanup(srv.Close)
+
+	model := New(WithAPIKey("test"), WithBaseURL(srv.URL))
+	stream, err := model.Stream(context.Background(), []core.Message{core.NewHumanMessage("hi")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok, err := stream.Next(); !ok || err != nil {
+		t.Fatalf("expected first chunk, got ok=%v err=%v", ok, err)
+	}
+	stream.Close()
+
+	select {
+	case <-canceled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("request was not canceled after closing the stream")
+	}
+}
+
+// streamToolCalls streams the given tool-call deltas and returns the calls on
+// the final message.
+func streamToolCalls(t *testing.T, deltas ...string) []core.ToolCall {
+	t.Helper()
+	return streamToolCallMessage(t, deltas...).ToolCalls
+}
+
+// streamToolCallMessage streams the tool call deltas and returns the final
+// message.
+func streamToolCallMessage(t *testing.T, deltas ...string) *core.AIMessage {
+	t.Helper()
+	var sse string
+	for _, d := range deltas {
+		sse += "data: {\"choices\":[{\"index\":0,\"delta\":{\"tool_calls\":[" + d + "]}}]}\n\n"
+	}
+	sse += "data: [DONE]\n\n"
+	srv := newTestServer(t, "text/event-stream", []byte(sse), nil)
+
+	model := New(WithAPIKey("test"), WithBaseURL(srv.URL))
+	stream, err := model.Stream(context.Background(), []core.Message{core.NewHumanMessage("hi")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	chunks, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(chunks) == 0 {
+		t.Fatal("expected a final tool-call message")
+	}
+	return chunks[len(chunks)-1]
+}
+
+func assertToolCalls(t *testing.T, got []core.ToolCall, want ...core.ToolCall) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("expected %d tool calls, got %d: %+v", len(want), len(got), got)
+	}
+	for i := range want {
+		if got[i].ID != want[i].ID || got[i].Name != want[i].Name || string(got[i].Args) != string(want[i].Args) {
+			t.Errorf("tool call %d: expected {%s %s %s}, got {%s %s %s}",
+				i, want[i].ID, want[i].Name, want[i].Args, got[i].ID, got[i].Name, got[i].Args)
+		}
+	}
+}
+
+func TestStreamToolCalls_Indexed(t *testing.T) {
+	got := streamToolCalls(t,
+		`{"index":0,"id":"call_1","function":{"name":"add","arguments":""}}`,
+		`{"index":0,"function":{"arguments":"{\"a\":1}"}}`,
+		`{"index":1,"id":"call_2","function":{"name":"mul","arguments":"{\"b\""}}`,
+		`{"index":1,"function":{"arguments":":2}"}}`,
+	)
+	assertToolCalls(t, got,
+		core.ToolCall{ID: "call_1", Name: "add", Args: []byte(`{"a":1}`)},
+		core.ToolCall{ID: "call_2", Name: "mul", Args: []byte(`{"b":2}`)},
+	)
+}
+
+func TestStreamToolCalls_MissingIndex(t *testing.T) {
+	got := streamToolCalls(t,
+		`{"id":"call_1","function":{"name":"add","arguments":"{\"a\""}}`,
+		`{"function":{"arguments":":1}"}}`,
+		`{"id":"call_2","function":{"name":"mul","arguments":""}}`,
+		`{"function":{"arguments":"{\"b\":2}"}}`,
+	)
+	assertToolCalls(t, got,
+		core.ToolCall{ID: "call_1", Name: "add", Args: []byte(`{"a":1}`)},
+		core.ToolCall{ID: "call_2", Name: "mul", Args: []byte(`{"b":2}`)},
+	)
+}
+
+func TestStreamToolCalls_ReusedIndex(t *testing.T) {
+	// Every call arrives on index 0; a new id marks the start of the next one.
+	got := streamToolCalls(t,
+		`{"index":0,"id":"call_1","function":{"name":"add","arguments":"{\"a\":1}"}}`,
+		`{"index":0,"id":"call_2","function":{"name":"mul","arguments":"{\"b\""}}`,
+		`{"index":0,"function":{"arguments":":2}"}}`,
+	)
+	assertToolCalls(t, got,
+		core.ToolCall{ID: "call_1", Name: "add", Args: []byte(`{"a":1}`)},
+		core.ToolCall{ID: "call_2", Name: "mul", Args: []byte(`{"b":2}`)},
+	)
+}
+
+func TestStreamToolCalls_Interleaved(t *testing.T) {
+	got := streamToolCalls(t,
+		`{"index":0,"id":"call_1","function":{"name":"add","arguments":"{\"a\""}}`,
+		`{"index":1,"id":"call_2","function":{"name":"mul","arguments":"{\"b\""}}`,
+		`{"index":0,"function":{"arguments":":1}"}}`,
+		`{"id":"call_2","function":{"arguments":":2}"}}`,
+	)
+	assertToolCalls(t, got,
+		core.ToolCall{ID: "call_1", Name: "add", Args: []byte(`{"a":1}`)},
+		core.ToolCall{ID: "call_2", Name: "mul", Args: []byte(`{"b":2}`)},
+	)
+}
+
+func TestStreamToolCalls_InvalidArgs(t *testing.T) {
+	msg := streamToolCallMessage(t,
+		`{"index":0,"id":"call_1","function":{"name":"add","arguments":"{\"a\":1}"}}`,
+		`{"index":1,"id":"call_2","function":{"name":"mul","arguments":"{\"a\":"}}`,
+		`{"index":2,"id":"call_3","function":{"name":"now","arguments":""}}`,
+	)
+	assertToolCalls(t, msg.ToolCalls,
+		core.ToolCall{ID: "call_1", Name: "add", Args: json.RawMessage(`{"a":1}`)},
+		core.ToolCall{ID: "call_3", Name: "now", Args: json.RawMessage(`{}`)},
+	)
+	if len(msg.InvalidToolCalls) != 1 {
+		t.Fatalf("expected 1 invalid tool call, got %+v", msg.InvalidToolCalls)
+	}
+	invalid := msg.InvalidToolCalls[0]
+	if invalid.ID != "call_2" || invalid.Name != "mul" || invalid.Args != `{"a":` || invalid.Error == "" {
+		t.Errorf("unexpected invalid tool call: %+v", invalid)
+	}
+}
+
+func TestChatModel_StreamLongLine(t *testing.T) {
+	// A single data line well past bufio.Scanner's 64KB default limit.
+	long := strings.Repeat("x", 200*1024)
+	sse := "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"" + long + "\"}}]}\n\n" +
+		"data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"!\"}}]}\n\n" +
+		"data: [DONE]\n\n"
+	srv := newTestServer(t, "text/event-stream", []byte(sse), nil)
+
+	model := New(WithAPIKey("test"), WithBaseURL(srv.URL))
+	stream, err := model.Stream(context.Background(), []core.Message{core.NewHumanMessage("hi")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	chunks, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var content string
+	for _, c := range chunks {
+		content += c.Content
+	}
+	if content != long+"!" {
+		t.Errorf("expected %d bytes of content, got %d", len(long)+1, len(content))
+	}
+}
+
+func TestChatModel_StreamFiresTokenCallbacks(t *testing.T) {
+	sse := "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"hel\"}}]}\n\n" +
+		"data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"lo\"}}]}\n\n" +
+		"data: [DONE]\n\n"
+	srv := newTestServer(t, "text/event-stream", []byte(sse), nil)
+
+	metrics := callbacks.NewMetricsHandler()
+	model := New(WithAPIKey("test"), WithBaseURL(srv.URL))
+	stream, err := model.Stream(context.Background(), []core.Message{core.NewHumanMessage("hi")},
+		core.WithCallbacks(metrics), core.WithRunID("run-1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := stream.Collect(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	snap := metrics.Snapshot()
+	if len(snap.Runs) != 1 {
+		t.Fatalf("expected 1 run, got %d", len(snap.Runs))
+	}
+	run := snap.Runs[0]
+	if run.RunID != "run-1" || run.Name != "ChatOpenAI" || run.Tokens != 2 || !run.Finished || run.Err != nil {
+		t.Errorf("unexpected run metrics: %+v", run)
+	}
+}
+
+func TestChatModel_OrganizationAndProjectHeaders(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices":[{"index":0,"message":{"role":"assistant","content":"ok"}}]}`))
+	}))
+	t.Cleanup(srv.Close)
+	msgs := []core.Message{core.NewHumanMessage("hi")}
+
+	model := New(WithAPIKey("test"), WithBaseURL(srv.URL))
+	if _, err := model.Invoke(context.Background(), msgs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := got["Openai-Organization"]; ok {
+		t.Error("expected no OpenAI-Organization header when unset")
+	}
+	if _, ok := got["Openai-Project"]; ok {
+		t.Error("expected no OpenAI-Project header when unset")
+	}
+
+	model = New(WithAPIKey("test"), WithBaseURL(srv.URL), WithOrganization("org-1"), WithProject("proj-1"))
+	if _, err := model.Invoke(context.Background(), msgs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v := got.Get("OpenAI-Organization"); v != "org-1" {
+		t.Errorf("expected OpenAI-Organization 'org-1', got %q", v)
+	}
+	if v := got.Get("OpenAI-Project"); v != "proj-1" {
+		t.Errorf("expected OpenAI-Project 'proj-1', got %q", v)
+	}
+}
+
+func TestChatModel_StreamStructuredOutput(t *testing.T) {
+	fragments := []string{`{"na`, `me":"Ada",`, `"age":`, `36}`}
+	var sse strings.Builder
+	for _, f := range fragments {
+		delta, _ := json.Marshal(map[string]any{
+			"choices": []any{map[string]any{"index": 0, "delta": map[string]any{"content": f}}},
+		})
+		sse.WriteString("data: " + string(delta) + "\n\n")
+	}
+	sse.WriteString("data: [DONE]\n\n")
+
+	var reqBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&reqBody)
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte(sse.String()))
+	}))
+	t.Cleanup(srv.Close)
+
+	schema := map[string]any{
+		"name": "person",
+		"schema": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"name": map[string]any{"type": "string"},
+				"age":  map[string]any{"type": "integer"},
+			},
+			"required": []string{"name", "age"},
+		},
+	}
+	model := New(WithAPIKey("test"), WithBaseURL(srv.URL)).WithStructuredOutput(schema)
+	stream, err := model.Stream(context.Background(), []core.Message{core.NewHumanMessage("who?")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	chunks, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if reqBody["stream"] != true {
+		t.Errorf("expected stream=true in request, got %v", reqBody["stream"])
+	}
+	format, _ := reqBody["response_format"].(map[string]any)
+	if format["type"] != "json_schema" {
+		t.Errorf("expected json_schema response format, got %v", reqBody["response_format"])
+	}
+	if js, _ := format["json_schema"].(map[string]any); js["name"] != "person" {
+		t.Errorf("expected schema name 'person', got %v", format["json_schema"])
+	}
+
+	if len(chunks) != len(fragments) {
+		t.Fatalf("expected %d partial chunks, got %d", len(fragments), len(chunks))
+	}
+	var merged strings.Builder
+	for _, c := range chunks {
+		merged.WriteString(c.Content)
+	}
+	var person struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+	if err := json.Unmarshal([]byte(merged.String()), &person); err != nil {
+		t.Fatalf("merged chunks are not valid JSON: %v (%q)", err, merged.String())
+	}
+	if person.Name != "Ada" || person.Age != 36 {
+		t.Errorf("unexpected value: %+v", person)
+	}
+}
+
+func TestChatModel_ParallelToolCalls(t *testing.T) {
+	msgs := []core.Message{core.NewHumanMessage("hi")}
+	tool := llms.ToolDefinition{Name: "add", Parameters: map[string]any{"type": "object"}}
+
+	// Unset by default, and never sent without tools.
+	req := New(WithAPIKey("test")).BindTools(tool).(*ChatModel).buildRequest(msgs, core.ApplyOptions(), false)
+	if _, ok := req["parallel_tool_calls"]; ok {
+		t.Errorf("expected parallel_tool_calls to be unset, got %v", req["parallel_tool_calls"])
+	}
+	req = New(WithAPIKey("test"), WithParallelToolCalls(false)).buildRequest(msgs, core.ApplyOptions(), false)
+	if _, ok := req["parallel_tool_calls"]; ok {
+		t.Error("expected parallel_tool_calls to be omitted without tools")
+	}
+
+	model := New(WithAPIKey("test"), WithParallelToolCalls(false)).BindTools(tool).(*ChatModel)
+	req = model.buildRequest(msgs, core.ApplyOptions(), false)
+	if req["parallel_tool_calls"] != false {
+		t.Errorf("expected parallel_tool_calls false, got %v", req["parallel_tool_calls"])
+	}
+
+	// The call option overrides the model option.
+	req = model.buildRequest(msgs, core.ApplyOptions(llms.WithParallelToolCalls(true)), false)
+	if req["parallel_tool_calls"] != true {
+		t.Errorf("expected parallel_tool_calls true, got %v", req["parallel_tool_calls"])
+	}
+}
+
+func TestChatModel_ContentBlocks(t *testing.T) {
+	msgs := []core.Message{
+		core.NewSystemMessage("Describe images."),
+		core.NewHumanMessageWithBlocks(
+			core.TextBlock("What is this?"),
+			core.ImageURLBlock("https://example.com/a.png"),
+			core.ImageDataBlock("image/png", []byte("ABC")),
+		),
+	}
+	req := New(WithAPIKey("test")).buildRequest(msgs, core.ApplyOptions(), false)
+	apiMsgs := req["messages"].([]map[string]any)
+
+	if apiMsgs[0]["content"] != "Describe images." {
+		t.Errorf("expected plain string content, got %v", apiMsgs[0]["content"])
+	}
+	parts := apiMsgs[1]["content"].([]map[string]any)
+	if len(parts) != 3 || parts[0]["type"] != "text" || parts[0]["text"] != "What is this?" {
+		t.Fatalf("unexpected content parts: %v", parts)
+	}
+	if url := parts[1]["image_url"].(map[string]any)["url"]; url != "https://example.com/a.png" {
+		t.Errorf("unexpected image url: %v", url)
+	}
+	if url := parts[2]["image_url"].(map[string]any)["url"]; url != "data:image/png;base64,QUJD" {
+		t.Errorf("expected a data URL, got %v", url)
+	}
+}
+
+func TestChatModel_StreamChunks(t *testing.T) {
+	sse := `data: {"id":"c1","choices":[{"index":0,"delta":{"role":"assistant","content":"Hi"}},{"index":1,"delta":{"content":"Yo"}}]}
+
+data: {"id":"c1","choices":[{"index":0,"delta":{"content":"!"},"finish_reason":"stop"},{"index":1,"delta":{"tool_calls":[{"index":0,"id":"call_1","function":{"name":"add","arguments":"{}"}}]},"finish_reason":"tool_calls"}]}
+
+data: {"id":"c1","choices":[],"usage":{"prompt_tokens":5,"completion_tokens":3,"total_tokens":8}}
+
+data: [DONE]
+
+`
+	srv := newTestServer(t, "text/event-stream", []byte(sse), nil)
+	model := New(WithAPIKey("test"), WithBaseURL(srv.URL))
+
+	stream, err := model.StreamChunks(context.Background(), []core.Message{core.NewHumanMessage("hi")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	chunks, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	merged := make(map[int]*core.AIMessageChunk)
+	for _, c := range chunks {
+		merged[c.Index] = merged[c.Index].Concat(c)
+	}
+	first, second := merged[0], merged[1]
+	if first.Content != "Hi!" || first.GenerationInfo["finish_reason"] != "stop" || first.ID != "c1" {
+		t.Errorf("unexpected first choice: %+v", first)
+	}
+	if u := first.UsageMetadata; u == nil || u.TotalTokens != 8 {
+		t.Errorf("expected usage on the first choice, got %+v", u)
+	}
+	if second.Content != "Yo" || second.GenerationInfo["finish_reason"] != "tool_calls" {
+		t.Errorf("unexpected second choice: %+v", second)
+	}
+	assertToolCalls(t, second.Message().ToolCalls, core.ToolCall{ID: "call_1", Name: "add", Args: json.RawMessage(`{}`)})
+}
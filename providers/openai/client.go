@@ -0,0 +1,78 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/LucaLanziani/langchain-go/internal"
+)
+
+// defaultHTTPClient returns the HTTP client used by ChatModel, Embeddings,
+// Audio and Images.
+func defaultHTTPClient() *http.Client {
+	return &http.Client{}
+}
+
+// post sends body to path with the authentication headers from opts and
+// returns the decoded response body. Non-200 responses are returned as
+// errors carrying the response text. The caller must close the body.
+func post(ctx context.Context, client *http.Client, opts *Options, path, contentType string, body io.Reader) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, opts.BaseURL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	setHeaders(req, opts)
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	decoded, err := internal.DecodeBody(resp)
+	if err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		errBody, _ := io.ReadAll(decoded)
+		decoded.Close()
+		return nil, fmt.Errorf("OpenAI API error (status %d): %s", resp.StatusCode, string(errBody))
+	}
+	return decoded, nil
+}
+
+// postJSON sends body as JSON to path and returns the response body.
+func postJSON(ctx context.Context, client *http.Client, opts *Options, path string, body any) ([]byte, error) {
+	reqJSON, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	respBody, err := post(ctx, client, opts, path, "application/json", bytes.NewReader(reqJSON))
+	if err != nil {
+		return nil, err
+	}
+	defer respBody.Close()
+
+	data, err := io.ReadAll(respBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return data, nil
+}
+
+// setHeaders sets the standard headers for OpenAI API requests.
+func setHeaders(req *http.Request, opts *Options) {
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", internal.AcceptEncoding)
+	req.Header.Set("Authorization", "Bearer "+opts.APIKey)
+	if opts.Organization != "" {
+		req.Header.Set("OpenAI-Organization", opts.Organization)
+	}
+	if opts.Project != "" {
+		req.Header.Set("OpenAI-Project", opts.Project)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"os"
 )
 
@@ -42,8 +41,7 @@ func (e *Embeddings) EmbedDocuments(ctx context.Context, texts []string) ([][]fl
 		"input": texts,
 	}
 
-	cm := &ChatModel{opts: e.opts, client: defaultHTTPClient()}
-	respBody, err := cm.doRequest(ctx, "/embeddings", reqBody)
+	respBody, err := postJSON(ctx, defaultHTTPClient(), e.opts, "/embeddings", reqBody)
 	if err != nil {
 		return nil, err
 	}
@@ -84,7 +82,3 @@ type embeddingData struct {
 	Embedding []float64 `json:"embedding"`
 	Index     int       `json:"index"`
 }
-
-func defaultHTTPClient() *http.Client {
-	return &http.Client{}
-}
@@ -0,0 +1,116 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// Images generates images from text prompts using OpenAI's images API.
+// It shares authentication and HTTP configuration with ChatModel.
+type Images struct {
+	opts   *Options
+	client *http.Client
+}
+
+// ImageResult is a single generated image. Exactly one of URL or B64JSON is
+// set, depending on the requested response format.
+type ImageResult struct {
+	// URL is a temporary link to the generated image.
+	URL string `json:"url,omitempty"`
+
+	// B64JSON is the base64-encoded image data.
+	B64JSON string `json:"b64_json,omitempty"`
+
+	// RevisedPrompt is the prompt the model actually used, if it rewrote it.
+	RevisedPrompt string `json:"revised_prompt,omitempty"`
+}
+
+// NewImages creates a new OpenAI Images instance.
+func NewImages(optFns ...OptionFunc) *Images {
+	opts := DefaultOptions()
+	for _, fn := range optFns {
+		fn(opts)
+	}
+	if opts.APIKey == "" {
+		opts.APIKey = os.Getenv("OPENAI_API_KEY")
+	}
+	return &Images{
+		opts:   opts,
+		client: defaultHTTPClient(),
+	}
+}
+
+// imageConfig holds per-call image generation settings.
+type imageConfig struct {
+	size           string
+	quality        string
+	n              int
+	responseFormat string
+}
+
+// ImageOption configures a single Generate call.
+type ImageOption func(*imageConfig)
+
+// WithImageSize sets the image dimensions (e.g., "1024x1024").
+func WithImageSize(size string) ImageOption {
+	return func(c *imageConfig) { c.size = size }
+}
+
+// WithImageQuality sets the image quality (e.g., "standard", "hd").
+func WithImageQuality(quality string) ImageOption {
+	return func(c *imageConfig) { c.quality = quality }
+}
+
+// WithImageCount sets the number of images to generate.
+func WithImageCount(n int) ImageOption {
+	return func(c *imageConfig) { c.n = n }
+}
+
+// WithImageResponseFormat sets whether images are returned as "url" or "b64_json".
+func WithImageResponseFormat(format string) ImageOption {
+	return func(c *imageConfig) { c.responseFormat = format }
+}
+
+// Generate creates images from a prompt via the /images/generations endpoint.
+func (im *Images) Generate(ctx context.Context, prompt string, opts ...ImageOption) ([]ImageResult, error) {
+	cfg := &imageConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	reqBody := map[string]any{
+		"model":  im.opts.ImageModel,
+		"prompt": prompt,
+	}
+	if cfg.size != "" {
+		reqBody["size"] = cfg.size
+	}
+	if cfg.quality != "" {
+		reqBody["quality"] = cfg.quality
+	}
+	if cfg.n > 0 {
+		reqBody["n"] = cfg.n
+	}
+	if cfg.responseFormat != "" {
+		reqBody["response_format"] = cfg.responseFormat
+	}
+
+	respBody, err := postJSON(ctx, im.client, im.opts, "/images/generations", reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp imageResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse image response: %w", err)
+	}
+	return resp.Data, nil
+}
+
+type imageResponse struct {
+	Created int64         `json:"created"`
+	Data    []ImageResult `json:"data"`
+}
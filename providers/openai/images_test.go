@@ -0,0 +1,46 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestImages_Generate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/images/generations" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		var req map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("bad request body: %v", err)
+		}
+		if req["model"] != "gpt-image-1" || req["prompt"] != "a gopher" || req["size"] != "512x512" || req["quality"] != "hd" || req["n"] != float64(2) {
+			t.Errorf("unexpected request: %v", req)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"created":1,"data":[
+			{"url":"https://example.com/1.png","revised_prompt":"a cute gopher"},
+			{"b64_json":"aGVsbG8="}
+		]}`))
+	}))
+	defer srv.Close()
+
+	images := NewImages(WithAPIKey("test"), WithBaseURL(srv.URL), WithImageModel("gpt-image-1"))
+	results, err := images.Generate(context.Background(), "a gopher",
+		WithImageSize("512x512"), WithImageQuality("hd"), WithImageCount(2))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].URL != "https://example.com/1.png" || results[0].RevisedPrompt != "a cute gopher" {
+		t.Errorf("unexpected first result: %+v", results[0])
+	}
+	if results[1].B64JSON != "aGVsbG8=" {
+		t.Errorf("unexpected second result: %+v", results[1])
+	}
+}
@@ -15,6 +15,9 @@ type Options struct {
 	// Organization is the OpenAI organization ID.
 	Organization string
 
+	// Project is the OpenAI project ID, for project-scoped API keys.
+	Project string
+
 	// Temperature controls randomness (0.0 to 2.0).
 	Temperature *float64
 
@@ -27,15 +30,31 @@ type Options struct {
 	// Stop sequences.
 	Stop []string
 
+	// ParallelToolCalls controls whether the model may request several tool
+	// calls in one turn. Nil leaves the provider default (enabled).
+	ParallelToolCalls *bool
+
 	// ResponseFormat can be "text" or "json_object".
 	ResponseFormat string
+
+	// TranscriptionModel is the speech-to-text model used by Audio.
+	TranscriptionModel string
+
+	// SpeechModel is the text-to-speech model used by Audio.
+	SpeechModel string
+
+	// ImageModel is the image generation model used by Images.
+	ImageModel string
 }
 
 // DefaultOptions returns sensible defaults.
 func DefaultOptions() *Options {
 	return &Options{
-		Model:   "gpt-4o",
-		BaseURL: "https://api.openai.com/v1",
+		Model:              "gpt-4o",
+		BaseURL:            "https://api.openai.com/v1",
+		TranscriptionModel: "whisper-1",
+		SpeechModel:        "tts-1",
+		ImageModel:         "dall-e-3",
 	}
 }
 
@@ -61,3 +80,29 @@ func WithBaseURL(url string) OptionFunc {
 func WithOrganization(org string) OptionFunc {
 	return func(o *Options) { o.Organization = org }
 }
+
+// WithProject sets the project ID sent in the OpenAI-Project header.
+func WithProject(id string) OptionFunc {
+	return func(o *Options) { o.Project = id }
+}
+
+// WithParallelToolCalls sets whether the model may request several tool
+// calls in one turn.
+func WithParallelToolCalls(v bool) OptionFunc {
+	return func(o *Options) { o.ParallelToolCalls = &v }
+}
+
+// WithTranscriptionModel sets the speech-to-text model used by Audio.
+func WithTranscriptionModel(model string) OptionFunc {
+	return func(o *Options) { o.TranscriptionModel = model }
+}
+
+// WithSpeechModel sets the text-to-speech model used by Audio.
+func WithSpeechModel(model string) OptionFunc {
+	return func(o *Options) { o.SpeechModel = model }
+}
+
+// WithImageModel sets the image generation model used by Images.
+func WithImageModel(model string) OptionFunc {
+	return func(o *Options) { o.ImageModel = model }
+}
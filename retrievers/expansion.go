@@ -0,0 +1,171 @@
+package retrievers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/LucaLanziani/langchain-go/core"
+	"github.com/LucaLanziani/langchain-go/llms"
+	"github.com/LucaLanziani/langchain-go/prompts"
+)
+
+// ExpansionMode controls how a QueryExpansionRetriever uses the model output.
+type ExpansionMode int
+
+const (
+	// ExpansionAppend appends the generated keywords to the original query.
+	ExpansionAppend ExpansionMode = iota
+
+	// ExpansionReplace searches with the model's rewritten query alone.
+	ExpansionReplace
+)
+
+// DefaultKeywordExpansionPrompt asks for keywords to append to the query.
+const DefaultKeywordExpansionPrompt = `Suggest keywords and synonyms that would help a search engine find documents relevant to the query below. Respond with the keywords only, separated by spaces, without repeating the query.
+
+Query: {query}`
+
+// DefaultRewriteExpansionPrompt asks for a rewritten, keyword-rich query.
+const DefaultRewriteExpansionPrompt = `Rewrite the query below for a search engine, keeping its terms and adding relevant keywords and synonyms. Respond with the rewritten query only.
+
+Query: {query}`
+
+// QueryExpansionRetriever enriches the query with model-generated keywords
+// and synonyms, then retrieves with the single expanded query from the base
+// retriever. If the expansion fails or comes back empty, the original query
+// is used.
+type QueryExpansionRetriever struct {
+	model  llms.ChatModel
+	base   Retriever
+	mode   ExpansionMode
+	prompt *prompts.PromptTemplate
+	name   string
+}
+
+// QueryExpansionOption configures a QueryExpansionRetriever.
+type QueryExpansionOption func(*QueryExpansionRetriever)
+
+// WithExpansionMode sets whether the expansion is appended to the query or
+// replaces it. Default is ExpansionAppend.
+func WithExpansionMode(mode ExpansionMode) QueryExpansionOption {
+	return func(r *QueryExpansionRetriever) { r.mode = mode }
+}
+
+// WithExpansionPrompt sets the prompt sent to the model. It receives the
+// query as {query}. By default the prompt matches the expansion mode.
+func WithExpansionPrompt(prompt *prompts.PromptTemplate) QueryExpansionOption {
+	return func(r *QueryExpansionRetriever) { r.prompt = prompt }
+}
+
+// NewQueryExpansionRetriever creates a retriever that expands queries with
+// model before searching base.
+func NewQueryExpansionRetriever(model llms.ChatModel, base Retriever, opts ...QueryExpansionOption) *QueryExpansionRetriever {
+	r := &QueryExpansionRetriever{
+		model: model,
+		base:  base,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.prompt == nil {
+		if r.mode == ExpansionReplace {
+			r.prompt = prompts.NewPromptTemplate(DefaultRewriteExpansionPrompt)
+		} else {
+			r.prompt = prompts.NewPromptTemplate(DefaultKeywordExpansionPrompt)
+		}
+	}
+	return r
+}
+
+// WithName sets the name for tracing.
+func (r *QueryExpansionRetriever) WithName(name string) *QueryExpansionRetriever {
+	r.name = name
+	return r
+}
+
+// GetName returns the retriever name.
+func (r *QueryExpansionRetriever) GetName() string {
+	if r.name != "" {
+		return r.name
+	}
+	return "QueryExpansionRetriever"
+}
+
+// ExpandQuery returns the query that would be sent to the base retriever.
+// Options are passed to the model.
+func (r *QueryExpansionRetriever) ExpandQuery(ctx context.Context, query string, opts ...core.Option) (string, error) {
+	text, err := r.prompt.Format(map[string]any{"query": query})
+	if err != nil {
+		return "", fmt.Errorf("failed to format expansion prompt: %w", err)
+	}
+	response, err := r.model.Invoke(ctx, []core.Message{core.NewHumanMessage(text)}, opts...)
+	if err != nil {
+		return "", fmt.Errorf("query expansion failed: %w", err)
+	}
+	expansion := strings.Join(strings.Fields(response.GetContent()), " ")
+	if expansion == "" {
+		return "", fmt.Errorf("query expansion returned no text")
+	}
+	if r.mode == ExpansionReplace {
+		return expansion, nil
+	}
+	return query + " " + expansion, nil
+}
+
+// GetRelevantDocuments expands the query and retrieves documents for it,
+// falling back to the original query if expansion fails. Options are passed
+// to the base retriever.
+func (r *QueryExpansionRetriever) GetRelevantDocuments(ctx context.Context, query string, opts ...Option) ([]*core.Document, error) {
+	return r.retrieve(ctx, query, nil, opts...)
+}
+
+// retrieve expands the query with modelOpts and searches base with opts.
+func (r *QueryExpansionRetriever) retrieve(ctx context.Context, query string, modelOpts []core.Option, opts ...Option) ([]*core.Document, error) {
+	expanded, err := r.ExpandQuery(ctx, query, modelOpts...)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		expanded = query
+	}
+	return r.base.GetRelevantDocuments(ctx, expanded, opts...)
+}
+
+// Invoke retrieves documents for the given query. The expansion model runs
+// as a child of the retriever's run, with the same options.
+func (r *QueryExpansionRetriever) Invoke(ctx context.Context, input string, opts ...core.Option) ([]*core.Document, error) {
+	cfg := core.ApplyOptions(opts...)
+	return r.retrieve(ctx, input, core.ChildOptions(cfg, 0, opts...))
+}
+
+// Stream returns a single-chunk stream of retrieved documents.
+func (r *QueryExpansionRetriever) Stream(ctx context.Context, input string, opts ...core.Option) (*core.StreamIterator[[]*core.Document], error) {
+	docs, err := r.Invoke(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan core.StreamChunk[[]*core.Document], 1)
+	ch <- core.StreamChunk[[]*core.Document]{Value: docs}
+	close(ch)
+	return core.NewStreamIterator(ch), nil
+}
+
+// Batch retrieves documents for multiple queries.
+func (r *QueryExpansionRetriever) Batch(ctx context.Context, inputs []string, opts ...core.Option) ([][]*core.Document, error) {
+	results := make([][]*core.Document, len(inputs))
+	for i, input := range inputs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		docs, err := r.Invoke(ctx, input, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("batch item %d: %w", i, err)
+		}
+		results[i] = docs
+	}
+	return results, nil
+}
+
+// Ensure QueryExpansionRetriever implements Retriever.
+var _ Retriever = (*QueryExpansionRetriever)(nil)
@@ -0,0 +1,152 @@
+package retrievers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/LucaLanziani/langchain-go/core"
+	"github.com/LucaLanziani/langchain-go/llms"
+)
+
+// expansionModel replies with a fixed text or error and records the prompt
+// and config of each call.
+type expansionModel struct {
+	reply   string
+	err     error
+	prompts []string
+	configs []*core.RunnableConfig
+}
+
+func (m *expansionModel) GetName() string { return "expansionModel" }
+func (m *expansionModel) Invoke(_ context.Context, input []core.Message, opts ...core.Option) (*core.AIMessage, error) {
+	m.prompts = append(m.prompts, input[len(input)-1].GetContent())
+	m.configs = append(m.configs, core.ApplyOptions(opts...))
+	if m.err != nil {
+		return nil, m.err
+	}
+	return core.NewAIMessage(m.reply), nil
+}
+func (m *expansionModel) Stream(ctx context.Context, input []core.Message, opts ...core.Option) (*core.StreamIterator[*core.AIMessage], error) {
+	msg, err := m.Invoke(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan core.StreamChunk[*core.AIMessage], 1)
+	ch <- core.StreamChunk[*core.AIMessage]{Value: msg}
+	close(ch)
+	return core.NewStreamIterator(ch), nil
+}
+func (m *expansionModel) Batch(ctx context.Context, inputs [][]core.Message, opts ...core.Option) ([]*core.AIMessage, error) {
+	results := make([]*core.AIMessage, len(inputs))
+	for i, in := range inputs {
+		results[i], _ = m.Invoke(ctx, in, opts...)
+	}
+	return results, nil
+}
+func (m *expansionModel) Generate(ctx context.Context, input []core.Message, opts ...core.Option) (*llms.ChatResult, error) {
+	msg, err := m.Invoke(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &llms.ChatResult{Generations: []*llms.ChatGeneration{{Message: msg}}}, nil
+}
+func (m *expansionModel) BindTools(...llms.ToolDefinition) llms.ChatModel    { return m }
+func (m *expansionModel) WithStructuredOutput(map[string]any) llms.ChatModel { return m }
+
+// queryRecorder is a Retriever that records the queries it receives.
+type queryRecorder struct {
+	queries []string
+}
+
+func (r *queryRecorder) GetName() string { return "queryRecorder" }
+func (r *queryRecorder) GetRelevantDocuments(_ context.Context, query string, _ ...Option) ([]*core.Document, error) {
+	r.queries = append(r.queries, query)
+	return []*core.Document{core.NewDocument(query)}, nil
+}
+func (r *queryRecorder) Invoke(ctx context.Context, input string, _ ...core.Option) ([]*core.Document, error) {
+	return r.GetRelevantDocuments(ctx, input)
+}
+func (r *queryRecorder) Stream(context.Context, string, ...core.Option) (*core.StreamIterator[[]*core.Document], error) {
+	return nil, errors.New("not implemented")
+}
+func (r *queryRecorder) Batch(context.Context, []string, ...core.Option) ([][]*core.Document, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestQueryExpansionRetrieverAppend(t *testing.T) {
+	model := &expansionModel{reply: "  laptop\nnotebook  computer "}
+	base := &queryRecorder{}
+	r := NewQueryExpansionRetriever(model, base)
+
+	docs, err := r.GetRelevantDocuments(context.Background(), "cheap pc")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(docs) != 1 || base.queries[0] != "cheap pc laptop notebook computer" {
+		t.Errorf("expected appended keywords, got %v", base.queries)
+	}
+	if !strings.Contains(model.prompts[0], "Query: cheap pc") {
+		t.Errorf("expected the query in the prompt, got %q", model.prompts[0])
+	}
+}
+
+func TestQueryExpansionRetrieverReplace(t *testing.T) {
+	model := &expansionModel{reply: "affordable desktop pc computer"}
+	base := &queryRecorder{}
+	r := NewQueryExpansionRetriever(model, base, WithExpansionMode(ExpansionReplace))
+
+	if _, err := r.Invoke(context.Background(), "cheap pc"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if base.queries[0] != "affordable desktop pc computer" {
+		t.Errorf("expected rewritten query, got %v", base.queries)
+	}
+	if !strings.Contains(model.prompts[0], "Rewrite the query") {
+		t.Errorf("expected the rewrite prompt, got %q", model.prompts[0])
+	}
+}
+
+func TestQueryExpansionRetrieverFallback(t *testing.T) {
+	for name, model := range map[string]*expansionModel{
+		"error": {err: errors.New("rate limited")},
+		"empty": {reply: "  \n "},
+	} {
+		t.Run(name, func(t *testing.T) {
+			base := &queryRecorder{}
+			r := NewQueryExpansionRetriever(model, base, WithExpansionMode(ExpansionReplace))
+			if _, err := r.GetRelevantDocuments(context.Background(), "cheap pc"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if base.queries[0] != "cheap pc" {
+				t.Errorf("expected fallback to the original query, got %v", base.queries)
+			}
+		})
+	}
+}
+
+func TestQueryExpansionRetrieverPassesOptionsToModel(t *testing.T) {
+	model := &expansionModel{reply: "laptop"}
+	r := NewQueryExpansionRetriever(model, &queryRecorder{})
+	handler := &core.BaseCallbackHandler{}
+
+	if _, err := r.Invoke(context.Background(), "cheap pc", core.WithRunID("run-1"), core.WithCallbacks(handler)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := r.Batch(context.Background(), []string{"a", "b"}, core.WithCallbacks(handler)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(model.configs) != 3 {
+		t.Fatalf("expected 3 model calls, got %d", len(model.configs))
+	}
+	cfg := model.configs[0]
+	if cfg.ParentRunID != "run-1" || cfg.RunID != core.ChildRunID("run-1", 0) {
+		t.Errorf("expected a child run of run-1, got %s under %s", cfg.RunID, cfg.ParentRunID)
+	}
+	for i, cfg := range model.configs {
+		if len(cfg.Callbacks) != 1 || cfg.Callbacks[0] != handler {
+			t.Errorf("call %d: expected the retriever's callbacks, got %v", i, cfg.Callbacks)
+		}
+	}
+}
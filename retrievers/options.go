@@ -0,0 +1,43 @@
+package retrievers
+
+// Options holds per-call retrieval settings. Zero values leave the
+// retriever's configured behavior unchanged.
+type Options struct {
+	// K is the number of documents to return. Zero uses the retriever's
+	// default.
+	K int
+
+	// Filter restricts results to documents whose metadata has each key set
+	// to the given value.
+	Filter map[string]any
+}
+
+// Option configures a single GetRelevantDocuments call.
+type Option func(*Options)
+
+// WithK sets the number of documents to return.
+func WithK(n int) Option {
+	return func(o *Options) { o.K = n }
+}
+
+// WithFilter restricts results to documents whose metadata matches every
+// key/value pair in filter. Calling it more than once merges the filters.
+func WithFilter(filter map[string]any) Option {
+	return func(o *Options) {
+		if o.Filter == nil {
+			o.Filter = make(map[string]any, len(filter))
+		}
+		for k, v := range filter {
+			o.Filter[k] = v
+		}
+	}
+}
+
+// ApplyOptions creates Options from the given option functions.
+func ApplyOptions(opts ...Option) *Options {
+	o := &Options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
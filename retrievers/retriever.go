@@ -17,7 +17,9 @@ type Retriever interface {
 	core.Runnable[string, []*core.Document]
 
 	// GetRelevantDocuments retrieves documents relevant to the query.
-	GetRelevantDocuments(ctx context.Context, query string) ([]*core.Document, error)
+	// Options such as WithK and WithFilter override the retriever's
+	// configuration for this call.
+	GetRelevantDocuments(ctx context.Context, query string, opts ...Option) ([]*core.Document, error)
 }
 
 // VectorStoreRetriever wraps a VectorStore as a Retriever.
@@ -53,8 +55,33 @@ func (r *VectorStoreRetriever) GetName() string {
 }
 
 // GetRelevantDocuments searches the vector store for relevant documents.
-func (r *VectorStoreRetriever) GetRelevantDocuments(ctx context.Context, query string) ([]*core.Document, error) {
-	return r.store.SimilaritySearch(ctx, query, r.k)
+// Filters are applied by the store if it implements
+// vectorstores.FilteredSearcher; otherwise the k nearest documents are
+// filtered afterwards, which may return fewer than k.
+func (r *VectorStoreRetriever) GetRelevantDocuments(ctx context.Context, query string, opts ...Option) ([]*core.Document, error) {
+	o := ApplyOptions(opts...)
+	k := r.k
+	if o.K > 0 {
+		k = o.K
+	}
+	if len(o.Filter) == 0 {
+		return r.store.SimilaritySearch(ctx, query, k)
+	}
+	if fs, ok := r.store.(vectorstores.FilteredSearcher); ok {
+		return fs.SimilaritySearchWithFilter(ctx, query, k, o.Filter)
+	}
+
+	docs, err := r.store.SimilaritySearch(ctx, query, k)
+	if err != nil {
+		return nil, err
+	}
+	filtered := docs[:0:0]
+	for _, doc := range docs {
+		if vectorstores.MatchesFilter(doc.Metadata, o.Filter) {
+			filtered = append(filtered, doc)
+		}
+	}
+	return filtered, nil
 }
 
 // Invoke retrieves documents for the given query.
@@ -78,6 +105,9 @@ func (r *VectorStoreRetriever) Stream(ctx context.Context, input string, opts ..
 func (r *VectorStoreRetriever) Batch(ctx context.Context, inputs []string, opts ...core.Option) ([][]*core.Document, error) {
 	results := make([][]*core.Document, len(inputs))
 	for i, input := range inputs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		docs, err := r.GetRelevantDocuments(ctx, input)
 		if err != nil {
 			return nil, fmt.Errorf("batch item %d: %w", i, err)
@@ -0,0 +1,88 @@
+package retrievers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/LucaLanziani/langchain-go/core"
+	"github.com/LucaLanziani/langchain-go/vectorstores"
+	"github.com/LucaLanziani/langchain-go/vectorstores/inmemory"
+)
+
+// constantEmbedder embeds every text as the same vector, so all documents
+// are equally similar to any query.
+type constantEmbedder struct{}
+
+func (constantEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float64, error) {
+	vecs := make([][]float64, len(texts))
+	for i := range texts {
+		vecs[i] = []float64{1, 0}
+	}
+	return vecs, nil
+}
+func (constantEmbedder) EmbedQuery(context.Context, string) ([]float64, error) {
+	return []float64{1, 0}, nil
+}
+
+// plainStore hides the FilteredSearcher implementation of the wrapped store.
+type plainStore struct {
+	vectorstores.VectorStore
+}
+
+func newTestStore(t *testing.T) *inmemory.Store {
+	t.Helper()
+	store := inmemory.New(constantEmbedder{})
+	var docs []*core.Document
+	for i, lang := range []string{"go", "go", "go", "python", "python", "rust"} {
+		docs = append(docs, core.NewDocument(lang, map[string]any{"lang": lang, "n": i}))
+	}
+	if _, err := store.AddDocuments(context.Background(), docs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return store
+}
+
+func TestVectorStoreRetrieverOptions(t *testing.T) {
+	ctx := context.Background()
+	r := NewVectorStoreRetriever(newTestStore(t), 4)
+
+	docs, err := r.GetRelevantDocuments(ctx, "q")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(docs) != 4 {
+		t.Errorf("expected the configured k of 4, got %d", len(docs))
+	}
+
+	docs, _ = r.GetRelevantDocuments(ctx, "q", WithK(2))
+	if len(docs) != 2 {
+		t.Errorf("expected 2 documents with WithK(2), got %d", len(docs))
+	}
+
+	docs, _ = r.GetRelevantDocuments(ctx, "q", WithFilter(map[string]any{"lang": "python"}))
+	if len(docs) != 2 {
+		t.Fatalf("expected both python documents, got %d", len(docs))
+	}
+	for _, doc := range docs {
+		if doc.Metadata["lang"] != "python" {
+			t.Errorf("unexpected document: %v", doc.Metadata)
+		}
+	}
+
+	docs, _ = r.GetRelevantDocuments(ctx, "q",
+		WithFilter(map[string]any{"lang": "go"}), WithFilter(map[string]any{"n": 1}))
+	if len(docs) != 1 || docs[0].Metadata["n"] != 1 {
+		t.Errorf("expected merged filters to match one document, got %v", docs)
+	}
+}
+
+func TestVectorStoreRetrieverFilterFallback(t *testing.T) {
+	r := NewVectorStoreRetriever(plainStore{newTestStore(t)}, 10)
+	docs, err := r.GetRelevantDocuments(context.Background(), "q", WithFilter(map[string]any{"lang": "go"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(docs) != 3 {
+		t.Errorf("expected the 3 go documents, got %d", len(docs))
+	}
+}
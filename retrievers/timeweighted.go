@@ -0,0 +1,252 @@
+package retrievers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/LucaLanziani/langchain-go/core"
+	"github.com/LucaLanziani/langchain-go/vectorstores"
+)
+
+// DefaultTimestampKey is the metadata key TimeWeightedReranker reads
+// document timestamps from.
+const DefaultTimestampKey = "timestamp"
+
+// TimeWeightedReranker re-scores similarity search results so that recent
+// documents rank higher. Each document's score becomes
+// similarity * decay(age), where age is the time since the document's
+// timestamp metadata.
+//
+//	reranker := retrievers.NewTimeWeightedReranker(0.01, time.Now)
+//	results, _ := store.SimilaritySearchWithScore(ctx, query, 20)
+//	results = reranker.Rerank(results)
+type TimeWeightedReranker struct {
+	decayRate     float64
+	now           func() time.Time
+	timestampKey  string
+	decay         func(age time.Duration) float64
+	undatedFactor float64
+}
+
+// TimeWeightedOption configures a TimeWeightedReranker.
+type TimeWeightedOption func(*TimeWeightedReranker)
+
+// WithTimestampKey sets the metadata key holding document timestamps.
+// Default is "timestamp".
+func WithTimestampKey(key string) TimeWeightedOption {
+	return func(r *TimeWeightedReranker) { r.timestampKey = key }
+}
+
+// WithDecayFunc replaces the default exponential decay. fn receives the age
+// of a document and returns the factor its similarity is multiplied by.
+func WithDecayFunc(fn func(age time.Duration) float64) TimeWeightedOption {
+	return func(r *TimeWeightedReranker) { r.decay = fn }
+}
+
+// WithUndatedFactor sets the factor by which the similarity of documents
+// without a readable timestamp is multiplied. The default of 1 keeps their
+// score unchanged, so they rank like brand-new documents; 0 sends them to
+// the bottom, and a value in between treats them as moderately stale.
+func WithUndatedFactor(factor float64) TimeWeightedOption {
+	return func(r *TimeWeightedReranker) { r.undatedFactor = factor }
+}
+
+// NewTimeWeightedReranker creates a reranker whose default decay is
+// (1 - decayRate) ^ hours, so a decayRate of 0.01 loses about 1% of a
+// document's score per hour of age. nowFn supplies the current time; if nil,
+// time.Now is used.
+func NewTimeWeightedReranker(decayRate float64, nowFn func() time.Time, opts ...TimeWeightedOption) *TimeWeightedReranker {
+	if nowFn == nil {
+		nowFn = time.Now
+	}
+	r := &TimeWeightedReranker{
+		decayRate:     decayRate,
+		now:           nowFn,
+		timestampKey:  DefaultTimestampKey,
+		undatedFactor: 1,
+	}
+	r.decay = r.exponentialDecay
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// exponentialDecay is the default decay function.
+func (r *TimeWeightedReranker) exponentialDecay(age time.Duration) float64 {
+	return math.Pow(1-r.decayRate, age.Hours())
+}
+
+// Rerank returns the results re-scored and sorted by descending score. The
+// input slice is not modified. Documents without a readable timestamp are
+// scored with the factor set by WithUndatedFactor; timestamps in the future
+// count as age zero. Ties keep their original order.
+func (r *TimeWeightedReranker) Rerank(results []vectorstores.DocumentWithScore) []vectorstores.DocumentWithScore {
+	now := r.now()
+	reranked := make([]vectorstores.DocumentWithScore, len(results))
+	for i, res := range results {
+		reranked[i] = res
+		if res.Document == nil {
+			continue
+		}
+		ts, ok := parseTimestamp(res.Document.Metadata[r.timestampKey])
+		if !ok {
+			reranked[i].Score = res.Score * r.undatedFactor
+			continue
+		}
+		age := now.Sub(ts)
+		if age < 0 {
+			age = 0
+		}
+		reranked[i].Score = res.Score * r.decay(age)
+	}
+	sort.SliceStable(reranked, func(i, j int) bool {
+		return reranked[i].Score > reranked[j].Score
+	})
+	return reranked
+}
+
+// parseTimestamp reads a timestamp stored as a time.Time, an RFC 3339
+// string, or Unix seconds.
+func parseTimestamp(v any) (time.Time, bool) {
+	switch ts := v.(type) {
+	case time.Time:
+		return ts, !ts.IsZero()
+	case *time.Time:
+		if ts != nil && !ts.IsZero() {
+			return *ts, true
+		}
+	case string:
+		if t, err := time.Parse(time.RFC3339, ts); err == nil {
+			return t, true
+		}
+	case int:
+		return time.Unix(int64(ts), 0), true
+	case int64:
+		return time.Unix(ts, 0), true
+	case float64:
+		sec, frac := math.Modf(ts)
+		return time.Unix(int64(sec), int64(frac*1e9)), true
+	}
+	return time.Time{}, false
+}
+
+// TimeWeightedRetriever runs a scored similarity search on a vector store,
+// reranks the candidates with a TimeWeightedReranker and returns the top k.
+type TimeWeightedRetriever struct {
+	store    vectorstores.VectorStore
+	reranker *TimeWeightedReranker
+	k        int
+	fetchK   int
+	name     string
+}
+
+// NewTimeWeightedRetriever creates a retriever that returns the k best
+// documents after time weighting. By default it reranks 4*k candidates, so
+// that recent documents just outside the k most similar can still rank.
+func NewTimeWeightedRetriever(store vectorstores.VectorStore, reranker *TimeWeightedReranker, k int) *TimeWeightedRetriever {
+	if k <= 0 {
+		k = 4
+	}
+	return &TimeWeightedRetriever{
+		store:    store,
+		reranker: reranker,
+		k:        k,
+		fetchK:   4 * k,
+	}
+}
+
+// WithFetchK sets the number of candidates fetched from the store before
+// reranking. Values below k are raised to k.
+func (r *TimeWeightedRetriever) WithFetchK(n int) *TimeWeightedRetriever {
+	r.fetchK = n
+	return r
+}
+
+// WithName sets the name for tracing.
+func (r *TimeWeightedRetriever) WithName(name string) *TimeWeightedRetriever {
+	r.name = name
+	return r
+}
+
+// GetName returns the retriever name.
+func (r *TimeWeightedRetriever) GetName() string {
+	if r.name != "" {
+		return r.name
+	}
+	return "TimeWeightedRetriever"
+}
+
+// GetRelevantDocuments fetches candidates, drops those not matching the
+// filter, reranks the rest and returns the top k.
+func (r *TimeWeightedRetriever) GetRelevantDocuments(ctx context.Context, query string, opts ...Option) ([]*core.Document, error) {
+	o := ApplyOptions(opts...)
+	k := r.k
+	if o.K > 0 {
+		k = o.K
+	}
+	fetchK := max(r.fetchK, k)
+
+	results, err := r.store.SimilaritySearchWithScore(ctx, query, fetchK)
+	if err != nil {
+		return nil, err
+	}
+	if len(o.Filter) > 0 {
+		filtered := results[:0:0]
+		for _, res := range results {
+			if res.Document != nil && vectorstores.MatchesFilter(res.Document.Metadata, o.Filter) {
+				filtered = append(filtered, res)
+			}
+		}
+		results = filtered
+	}
+
+	results = r.reranker.Rerank(results)
+	if len(results) > k {
+		results = results[:k]
+	}
+	docs := make([]*core.Document, len(results))
+	for i, res := range results {
+		docs[i] = res.Document
+	}
+	return docs, nil
+}
+
+// Invoke retrieves documents for the given query.
+func (r *TimeWeightedRetriever) Invoke(ctx context.Context, input string, opts ...core.Option) ([]*core.Document, error) {
+	return r.GetRelevantDocuments(ctx, input)
+}
+
+// Stream returns a single-chunk stream of retrieved documents.
+func (r *TimeWeightedRetriever) Stream(ctx context.Context, input string, opts ...core.Option) (*core.StreamIterator[[]*core.Document], error) {
+	docs, err := r.GetRelevantDocuments(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan core.StreamChunk[[]*core.Document], 1)
+	ch <- core.StreamChunk[[]*core.Document]{Value: docs}
+	close(ch)
+	return core.NewStreamIterator(ch), nil
+}
+
+// Batch retrieves documents for multiple queries.
+func (r *TimeWeightedRetriever) Batch(ctx context.Context, inputs []string, opts ...core.Option) ([][]*core.Document, error) {
+	results := make([][]*core.Document, len(inputs))
+	for i, input := range inputs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		docs, err := r.GetRelevantDocuments(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("batch item %d: %w", i, err)
+		}
+		results[i] = docs
+	}
+	return results, nil
+}
+
+// Ensure TimeWeightedRetriever implements Retriever.
+var _ Retriever = (*TimeWeightedRetriever)(nil)
@@ -0,0 +1,150 @@
+package retrievers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/LucaLanziani/langchain-go/core"
+	"github.com/LucaLanziani/langchain-go/vectorstores"
+	"github.com/LucaLanziani/langchain-go/vectorstores/inmemory"
+)
+
+func scoredDoc(content string, score float64, metadata map[string]any) vectorstores.DocumentWithScore {
+	return vectorstores.DocumentWithScore{
+		Document: core.NewDocument(content, metadata),
+		Score:    score,
+	}
+}
+
+func TestTimeWeightedRerankerPrefersRecent(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	reranker := NewTimeWeightedReranker(0.1, func() time.Time { return now })
+
+	input := []vectorstores.DocumentWithScore{
+		scoredDoc("old", 0.9, map[string]any{"timestamp": now.Add(-24 * time.Hour)}),
+		scoredDoc("recent", 0.8, map[string]any{"timestamp": now.Add(-time.Hour).Format(time.RFC3339)}),
+		scoredDoc("undated", 0.5, nil),
+		scoredDoc("unix", 0.7, map[string]any{"timestamp": now.Unix()}),
+	}
+	results := reranker.Rerank(input)
+
+	var order []string
+	for _, r := range results {
+		order = append(order, r.Document.PageContent)
+	}
+	want := []string{"recent", "unix", "undated", "old"}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("expected order %v, got %v", want, order)
+		}
+	}
+
+	if got, want := results[0].Score, 0.8*0.9; math.Abs(got-want) > 1e-9 {
+		t.Errorf("expected recent score %v, got %v", want, got)
+	}
+	if results[2].Score != 0.5 {
+		t.Errorf("expected undated document to keep its score, got %v", results[2].Score)
+	}
+	if input[0].Score != 0.9 || input[0].Document.PageContent != "old" {
+		t.Error("expected input to be left unmodified")
+	}
+}
+
+func TestTimeWeightedRerankerOptions(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	var ages []time.Duration
+	reranker := NewTimeWeightedReranker(0, func() time.Time { return now },
+		WithTimestampKey("updated_at"),
+		WithDecayFunc(func(age time.Duration) float64 {
+			ages = append(ages, age)
+			return 0.5
+		}),
+	)
+
+	results := reranker.Rerank([]vectorstores.DocumentWithScore{
+		scoredDoc("a", 1, map[string]any{"updated_at": now.Add(-2 * time.Hour)}),
+		scoredDoc("b", 1, map[string]any{"timestamp": now}),
+		scoredDoc("future", 1, map[string]any{"updated_at": now.Add(time.Hour)}),
+	})
+
+	if len(ages) != 2 || ages[0] != 2*time.Hour || ages[1] != 0 {
+		t.Errorf("expected ages [2h 0s], got %v", ages)
+	}
+	if results[0].Document.PageContent != "b" || results[0].Score != 1 {
+		t.Errorf("expected document without the configured key first, got %+v", results[0])
+	}
+}
+
+func TestTimeWeightedRerankerUndatedFactor(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	input := []vectorstores.DocumentWithScore{
+		scoredDoc("undated", 0.9, nil),
+		scoredDoc("dated", 0.8, map[string]any{"timestamp": now.Add(-time.Hour)}),
+	}
+
+	reranker := NewTimeWeightedReranker(0.01, func() time.Time { return now }, WithUndatedFactor(0.5))
+	results := reranker.Rerank(input)
+	if results[0].Document.PageContent != "dated" {
+		t.Errorf("expected the dated document first, got %q", results[0].Document.PageContent)
+	}
+	if got := results[1].Score; math.Abs(got-0.45) > 1e-9 {
+		t.Errorf("expected undated score 0.45, got %v", got)
+	}
+
+	results = NewTimeWeightedReranker(0.01, func() time.Time { return now }, WithUndatedFactor(0)).Rerank(input)
+	if results[1].Document.PageContent != "undated" || results[1].Score != 0 {
+		t.Errorf("expected a zero factor to send the undated document last, got %+v", results[1])
+	}
+}
+
+func TestTimeWeightedRetriever(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	store := inmemory.New(constantEmbedder{})
+	var docs []*core.Document
+	for i, hours := range []int{48, 1, 24, 2, 72} {
+		lang := "go"
+		if i%2 == 1 {
+			lang = "python"
+		}
+		docs = append(docs, core.NewDocument(fmt.Sprintf("%dh", hours), map[string]any{
+			"timestamp": now.Add(-time.Duration(hours) * time.Hour),
+			"lang":      lang,
+		}))
+	}
+	if _, err := store.AddDocuments(ctx, docs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r := NewTimeWeightedRetriever(store, NewTimeWeightedReranker(0.1, func() time.Time { return now }), 2)
+
+	contents := func(docs []*core.Document) []string {
+		var out []string
+		for _, d := range docs {
+			out = append(out, d.PageContent)
+		}
+		return out
+	}
+
+	got, err := r.Invoke(ctx, "q")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c := contents(got); len(c) != 2 || c[0] != "1h" || c[1] != "2h" {
+		t.Errorf("expected the two most recent documents, got %v", c)
+	}
+
+	got, _ = r.GetRelevantDocuments(ctx, "q", WithK(3), WithFilter(map[string]any{"lang": "go"}))
+	if c := contents(got); len(c) != 3 || c[0] != "24h" || c[1] != "48h" || c[2] != "72h" {
+		t.Errorf("expected the go documents by recency, got %v", c)
+	}
+
+	// A fetchK below k still fetches k candidates.
+	r.WithFetchK(1)
+	got, _ = r.GetRelevantDocuments(ctx, "q")
+	if len(got) != 2 {
+		t.Errorf("expected fetchK to be raised to k, got %d documents", len(got))
+	}
+}
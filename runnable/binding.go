@@ -0,0 +1,61 @@
+package runnable
+
+import (
+	"context"
+
+	"github.com/LucaLanziani/langchain-go/core"
+)
+
+// Binding wraps a runnable with a set of pre-bound options that are applied
+// to every call. It implements Runnable[I, O].
+type Binding[I, O any] struct {
+	inner core.Runnable[I, O]
+	opts  []core.Option
+	name  string
+}
+
+// WithConfig binds options (callbacks, tags, model overrides, ...) onto a
+// runnable so they don't need to be passed at every call. Bound options are
+// applied first, so options passed at the call site take precedence.
+func WithConfig[I, O any](inner core.Runnable[I, O], opts ...core.Option) *Binding[I, O] {
+	return &Binding[I, O]{inner: inner, opts: opts}
+}
+
+// WithName sets the name for tracing.
+func (b *Binding[I, O]) WithName(name string) *Binding[I, O] {
+	b.name = name
+	return b
+}
+
+// GetName returns the name of the wrapped runnable unless overridden.
+func (b *Binding[I, O]) GetName() string {
+	if b.name != "" {
+		return b.name
+	}
+	return b.inner.GetName()
+}
+
+// Invoke runs the wrapped runnable with the bound options.
+func (b *Binding[I, O]) Invoke(ctx context.Context, input I, opts ...core.Option) (O, error) {
+	return b.inner.Invoke(ctx, input, b.mergeOptions(opts)...)
+}
+
+// Stream streams from the wrapped runnable with the bound options.
+func (b *Binding[I, O]) Stream(ctx context.Context, input I, opts ...core.Option) (*core.StreamIterator[O], error) {
+	return b.inner.Stream(ctx, input, b.mergeOptions(opts)...)
+}
+
+// Batch runs the wrapped runnable's Batch with the bound options.
+func (b *Binding[I, O]) Batch(ctx context.Context, inputs []I, opts ...core.Option) ([]O, error) {
+	return b.inner.Batch(ctx, inputs, b.mergeOptions(opts)...)
+}
+
+// mergeOptions returns the bound options followed by the call-site options.
+func (b *Binding[I, O]) mergeOptions(opts []core.Option) []core.Option {
+	merged := make([]core.Option, 0, len(b.opts)+len(opts))
+	merged = append(merged, b.opts...)
+	return append(merged, opts...)
+}
+
+// Ensure Binding implements Runnable.
+var _ core.Runnable[any, any] = (*Binding[any, any])(nil)
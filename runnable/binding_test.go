@@ -0,0 +1,88 @@
+package runnable
+
+import (
+	"context"
+	"testing"
+
+	"github.com/LucaLanziani/langchain-go/core"
+)
+
+// configEcho returns the config built from the options it receives.
+type configEcho struct{}
+
+func (configEcho) GetName() string { return "configEcho" }
+func (configEcho) Invoke(_ context.Context, _ string, opts ...core.Option) (*core.RunnableConfig, error) {
+	return core.ApplyOptions(opts...), nil
+}
+func (c configEcho) Stream(ctx context.Context, input string, opts ...core.Option) (*core.StreamIterator[*core.RunnableConfig], error) {
+	cfg, _ := c.Invoke(ctx, input, opts...)
+	ch := make(chan core.StreamChunk[*core.RunnableConfig], 1)
+	ch <- core.StreamChunk[*core.RunnableConfig]{Value: cfg}
+	close(ch)
+	return core.NewStreamIterator(ch), nil
+}
+func (c configEcho) Batch(ctx context.Context, inputs []string, opts ...core.Option) ([]*core.RunnableConfig, error) {
+	results := make([]*core.RunnableConfig, len(inputs))
+	for i, in := range inputs {
+		results[i], _ = c.Invoke(ctx, in, opts...)
+	}
+	return results, nil
+}
+
+func TestWithConfig_Invoke(t *testing.T) {
+	bound := WithConfig[string, *core.RunnableConfig](configEcho{},
+		core.WithRunName("bound"),
+		core.WithTags("bound-tag"),
+	)
+
+	cfg, err := bound.Invoke(context.Background(), "x")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.RunName != "bound" {
+		t.Errorf("expected bound RunName, got %q", cfg.RunName)
+	}
+
+	// Call-site options take precedence and tags accumulate.
+	cfg, err = bound.Invoke(context.Background(), "x", core.WithRunName("call"), core.WithTags("call-tag"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.RunName != "call" {
+		t.Errorf("expected call-site RunName, got %q", cfg.RunName)
+	}
+	if len(cfg.Tags) != 2 {
+		t.Errorf("expected 2 tags, got %v", cfg.Tags)
+	}
+}
+
+func TestWithConfig_Batch(t *testing.T) {
+	bound := WithConfig[string, *core.RunnableConfig](configEcho{}, core.WithRunName("bound"))
+	results, err := bound.Batch(context.Background(), []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, cfg := range results {
+		if cfg.RunName != "bound" {
+			t.Errorf("item %d: expected bound RunName, got %q", i, cfg.RunName)
+		}
+	}
+}
+
+func TestWithConfig_Stream(t *testing.T) {
+	bound := WithConfig[string, *core.RunnableConfig](configEcho{}, core.WithRunName("bound"))
+	stream, err := bound.Stream(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg, ok, err := stream.Next()
+	if err != nil || !ok {
+		t.Fatalf("expected a chunk, got ok=%v err=%v", ok, err)
+	}
+	if cfg.RunName != "bound" {
+		t.Errorf("expected bound RunName, got %q", cfg.RunName)
+	}
+	if bound.GetName() != "configEcho" {
+		t.Errorf("expected inner name, got %q", bound.GetName())
+	}
+}
@@ -81,6 +81,9 @@ func (b *Branch[I, O]) Stream(ctx context.Context, input I, opts ...core.Option)
 func (b *Branch[I, O]) Batch(ctx context.Context, inputs []I, opts ...core.Option) ([]O, error) {
 	results := make([]O, len(inputs))
 	for i, input := range inputs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		result, err := b.Invoke(ctx, input, opts...)
 		if err != nil {
 			return nil, fmt.Errorf("batch item %d: %w", i, err)
@@ -53,6 +53,9 @@ func (l *Lambda[I, O]) Stream(ctx context.Context, input I, opts ...core.Option)
 func (l *Lambda[I, O]) Batch(ctx context.Context, inputs []I, opts ...core.Option) ([]O, error) {
 	results := make([]O, len(inputs))
 	for i, input := range inputs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		result, err := l.fn(ctx, input)
 		if err != nil {
 			return nil, err
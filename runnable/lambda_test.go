@@ -2,6 +2,7 @@ package runnable
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"testing"
 )
@@ -34,6 +35,39 @@ func TestLambdaBatch(t *testing.T) {
 	}
 }
 
+func TestLambdaBatchStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := 0
+	l := NewLambda(func(_ context.Context, n int) (int, error) {
+		calls++
+		if n == 2 {
+			cancel()
+		}
+		return n, nil
+	})
+
+	_, err := l.Batch(ctx, []int{1, 2, 3, 4})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected batch to stop after 2 items, ran %d", calls)
+	}
+
+	// A batch on an already-canceled context runs nothing.
+	calls = 0
+	seq := Pipe2[int, int, int](l, NewLambda(func(_ context.Context, n int) (int, error) { return n, nil }))
+	_, err = seq.Batch(ctx, []int{1, 2})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled from sequence, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("expected no items to run on a canceled context, ran %d", calls)
+	}
+}
+
 func TestLambdaGetName(t *testing.T) {
 	l := NewLambda(func(_ context.Context, s string) (string, error) { return s, nil })
 	if l.GetName() != "RunnableLambda" {
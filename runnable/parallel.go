@@ -3,6 +3,7 @@ package runnable
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/LucaLanziani/langchain-go/core"
@@ -58,6 +59,7 @@ func (p *Parallel[I]) GetName() string {
 }
 
 // Invoke runs all branches in parallel and collects results into a map.
+// Branches are numbered in key order to derive their child run IDs.
 func (p *Parallel[I]) Invoke(ctx context.Context, input I, opts ...core.Option) (map[string]any, error) {
 	cfg := core.ApplyOptions(opts...)
 
@@ -72,16 +74,19 @@ func (p *Parallel[I]) Invoke(ctx context.Context, input I, opts ...core.Option)
 		sem = make(chan struct{}, cfg.MaxConcurrency)
 	}
 
-	for _, key := range p.keys {
+	keys := append([]string(nil), p.keys...)
+	sort.Strings(keys)
+	for i, key := range keys {
 		key := key
 		fn := p.branches[key]
+		childOpts := core.ChildOptions(cfg, i, opts...)
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
 			sem <- struct{}{}
 			defer func() { <-sem }()
 
-			result, err := fn(ctx, input, opts...)
+			result, err := fn(ctx, input, childOpts...)
 			mu.Lock()
 			if err != nil {
 				errs[key] = err
@@ -118,6 +123,9 @@ func (p *Parallel[I]) Stream(ctx context.Context, input I, opts ...core.Option)
 func (p *Parallel[I]) Batch(ctx context.Context, inputs []I, opts ...core.Option) ([]map[string]any, error) {
 	results := make([]map[string]any, len(inputs))
 	for i, input := range inputs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		result, err := p.Invoke(ctx, input, opts...)
 		if err != nil {
 			return nil, fmt.Errorf("batch item %d: %w", i, err)
@@ -123,6 +123,9 @@ func (a *Assign[I]) Stream(ctx context.Context, input I, opts ...core.Option) (*
 func (a *Assign[I]) Batch(ctx context.Context, inputs []I, opts ...core.Option) ([]map[string]any, error) {
 	results := make([]map[string]any, len(inputs))
 	for i, input := range inputs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		result, err := a.Invoke(ctx, input, opts...)
 		if err != nil {
 			return nil, err
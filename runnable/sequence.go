@@ -40,11 +40,14 @@ func (s *Sequence[I, O]) WithName(name string) *Sequence[I, O] {
 }
 
 // Invoke runs all steps sequentially, passing each output as the next input.
+// Step i runs with the RunID core.ChildRunID(runID, i), where runID is the
+// sequence's own run ID.
 func (s *Sequence[I, O]) Invoke(ctx context.Context, input I, opts ...core.Option) (O, error) {
+	cfg := core.ApplyOptions(opts...)
 	var current any = input
 	var zero O
 	for i, st := range s.steps {
-		result, err := st.invoke(ctx, current, opts...)
+		result, err := st.invoke(ctx, current, core.ChildOptions(cfg, i, opts...)...)
 		if err != nil {
 			return zero, fmt.Errorf("step %d (%s): %w", i, st.name, err)
 		}
@@ -74,6 +77,9 @@ func (s *Sequence[I, O]) Stream(ctx context.Context, input I, opts ...core.Optio
 func (s *Sequence[I, O]) Batch(ctx context.Context, inputs []I, opts ...core.Option) ([]O, error) {
 	results := make([]O, len(inputs))
 	for i, input := range inputs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		result, err := s.Invoke(ctx, input, opts...)
 		if err != nil {
 			return nil, fmt.Errorf("batch item %d: %w", i, err)
@@ -119,3 +119,55 @@ func TestSequenceGetName(t *testing.T) {
 		t.Errorf("expected 'MyChain', got %q", chain.GetName())
 	}
 }
+
+// runIDRecorder records the run IDs it is invoked with and passes its input through.
+type runIDRecorder struct {
+	runIDs    []string
+	parentIDs []string
+}
+
+func (r *runIDRecorder) GetName() string { return "runIDRecorder" }
+func (r *runIDRecorder) Invoke(_ context.Context, input string, opts ...core.Option) (string, error) {
+	cfg := core.ApplyOptions(opts...)
+	r.runIDs = append(r.runIDs, cfg.RunID)
+	r.parentIDs = append(r.parentIDs, cfg.ParentRunID)
+	return input, nil
+}
+func (r *runIDRecorder) Stream(ctx context.Context, input string, opts ...core.Option) (*core.StreamIterator[string], error) {
+	out, err := r.Invoke(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan core.StreamChunk[string], 1)
+	ch <- core.StreamChunk[string]{Value: out}
+	close(ch)
+	return core.NewStreamIterator(ch), nil
+}
+func (r *runIDRecorder) Batch(ctx context.Context, inputs []string, opts ...core.Option) ([]string, error) {
+	return inputs, nil
+}
+
+func TestSequenceChildRunIDs(t *testing.T) {
+	first, second := &runIDRecorder{}, &runIDRecorder{}
+	seq := Pipe2[string, string, string](first, second)
+
+	for i := 0; i < 2; i++ {
+		if _, err := seq.Invoke(context.Background(), "x", core.WithRunID("run-1")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	for i, rec := range []*runIDRecorder{first, second} {
+		want := core.ChildRunID("run-1", i)
+		for _, got := range rec.runIDs {
+			if got != want {
+				t.Errorf("step %d: expected run ID %q, got %q", i, want, got)
+			}
+		}
+		for _, got := range rec.parentIDs {
+			if got != "run-1" {
+				t.Errorf("step %d: expected parent run ID 'run-1', got %q", i, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package runnable
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/LucaLanziani/langchain-go/core"
+)
+
+// InputValidator checks a map input against a JSON-Schema-style object
+// schema (the same shape used for tool arguments) and passes through a
+// coerced copy. Place it at the head of a sequence to validate raw input,
+// e.g. decoded JSON, before it reaches a chain.
+// It implements Runnable[map[string]any, map[string]any].
+//
+// Required keys must be present. Properties with a "type" of string,
+// integer, number, boolean, array, or object are type-checked; nested object
+// schemas are validated recursively. Strings are coerced to integer, number,
+// or boolean where they parse cleanly, and whole floats (as produced by
+// encoding/json) are coerced to int. Keys not in the schema pass through.
+type InputValidator struct {
+	schema map[string]any
+	name   string
+}
+
+// NewInputValidator creates an InputValidator for the given object schema.
+func NewInputValidator(schema map[string]any) *InputValidator {
+	return &InputValidator{schema: schema}
+}
+
+// WithName sets the name for tracing.
+func (v *InputValidator) WithName(name string) *InputValidator {
+	v.name = name
+	return v
+}
+
+// GetName returns the name of this validator.
+func (v *InputValidator) GetName() string {
+	if v.name != "" {
+		return v.name
+	}
+	return "InputValidator"
+}
+
+// Invoke validates input and returns a coerced copy. All problems are
+// reported together in the error.
+func (v *InputValidator) Invoke(ctx context.Context, input map[string]any, opts ...core.Option) (map[string]any, error) {
+	var problems []string
+	out := validateObject(v.schema, input, "", &problems)
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid input: %s", strings.Join(problems, "; "))
+	}
+	return out, nil
+}
+
+// Stream returns a single-chunk stream of the validated input.
+func (v *InputValidator) Stream(ctx context.Context, input map[string]any, opts ...core.Option) (*core.StreamIterator[map[string]any], error) {
+	result, err := v.Invoke(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan core.StreamChunk[map[string]any], 1)
+	ch <- core.StreamChunk[map[string]any]{Value: result}
+	close(ch)
+	return core.NewStreamIterator(ch), nil
+}
+
+// Batch validates each input.
+func (v *InputValidator) Batch(ctx context.Context, inputs []map[string]any, opts ...core.Option) ([]map[string]any, error) {
+	results := make([]map[string]any, len(inputs))
+	for i, input := range inputs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		result, err := v.Invoke(ctx, input, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("batch item %d: %w", i, err)
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
+// validateObject validates input against an object schema, appending any
+// problems, and returns a copy with coerced values. prefix is the path of
+// input within the top-level value, used in messages.
+func validateObject(schema map[string]any, input map[string]any, prefix string, problems *[]string) map[string]any {
+	out := make(map[string]any, len(input))
+	for k, val := range input {
+		out[k] = val
+	}
+
+	for _, key := range schemaRequired(schema) {
+		if _, ok := input[key]; !ok {
+			*problems = append(*problems, fmt.Sprintf("missing required key %q", prefix+key))
+		}
+	}
+
+	props, _ := schema["properties"].(map[string]any)
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		val, ok := input[key]
+		if !ok {
+			continue
+		}
+		propSchema, _ := props[key].(map[string]any)
+		coerced, err := coerceValue(propSchema, val, prefix+key+".", problems)
+		if err != nil {
+			*problems = append(*problems, fmt.Sprintf("key %q: %v", prefix+key, err))
+			continue
+		}
+		out[key] = coerced
+	}
+	return out
+}
+
+// schemaRequired returns the schema's required keys, accepting both []string
+// (as built in Go) and []any (as decoded from JSON).
+func schemaRequired(schema map[string]any) []string {
+	switch req := schema["required"].(type) {
+	case []string:
+		return req
+	case []any:
+		keys := make([]string, 0, len(req))
+		for _, k := range req {
+			if s, ok := k.(string); ok {
+				keys = append(keys, s)
+			}
+		}
+		return keys
+	}
+	return nil
+}
+
+// coerceValue checks val against a property schema and returns it,
+// converted where a simple coercion applies.
+func coerceValue(schema map[string]any, val any, prefix string, problems *[]string) (any, error) {
+	typ, _ := schema["type"].(string)
+	switch typ {
+	case "string":
+		if s, ok := val.(string); ok {
+			return s, nil
+		}
+	case "integer":
+		switch n := val.(type) {
+		case json.Number:
+			if i, err := n.Int64(); err == nil {
+				if i, ok := toInt(i); ok {
+					return i, nil
+				}
+			} else if f, err := n.Float64(); err == nil {
+				if i, ok := floatToInt(f); ok {
+					return i, nil
+				}
+			}
+		case string:
+			if i, err := strconv.Atoi(strings.TrimSpace(n)); err == nil {
+				return i, nil
+			}
+		default:
+			if i, ok := toInt(val); ok {
+				return i, nil
+			}
+		}
+	case "number":
+		switch n := val.(type) {
+		case json.Number:
+			if f, err := n.Float64(); err == nil {
+				return f, nil
+			}
+		case string:
+			if f, err := strconv.ParseFloat(strings.TrimSpace(n), 64); err == nil {
+				return f, nil
+			}
+		default:
+			if f, ok := toFloat(val); ok {
+				return f, nil
+			}
+		}
+	case "boolean":
+		switch b := val.(type) {
+		case bool:
+			return b, nil
+		case string:
+			if parsed, err := strconv.ParseBool(strings.TrimSpace(b)); err == nil {
+				return parsed, nil
+			}
+		}
+	case "array":
+		if val != nil && reflect.TypeOf(val).Kind() == reflect.Slice {
+			return val, nil
+		}
+	case "object":
+		if m, ok := val.(map[string]any); ok {
+			return validateObject(schema, m, prefix, problems), nil
+		}
+	default:
+		// No type constraint.
+		return val, nil
+	}
+	return nil, fmt.Errorf("expected %s, got %T %s", typ, val, describeValue(val))
+}
+
+// toInt converts any Go integer, or a float with no fractional part, to an
+// int. It fails if the value doesn't fit.
+func toInt(val any) (int, bool) {
+	v := reflect.ValueOf(val)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := v.Int()
+		if i < math.MinInt || i > math.MaxInt {
+			return 0, false
+		}
+		return int(i), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := v.Uint()
+		if u > math.MaxInt {
+			return 0, false
+		}
+		return int(u), true
+	case reflect.Float32, reflect.Float64:
+		return floatToInt(v.Float())
+	}
+	return 0, false
+}
+
+// floatToInt converts f to an int if it is a whole number within int range.
+func floatToInt(f float64) (int, bool) {
+	limit := math.Ldexp(1, strconv.IntSize-1)
+	if f != math.Trunc(f) || f < -limit || f >= limit {
+		return 0, false
+	}
+	return int(f), true
+}
+
+// toFloat converts any Go integer or float to a float64.
+func toFloat(val any) (float64, bool) {
+	v := reflect.ValueOf(val)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// describeValue renders a short form of val for error messages.
+func describeValue(val any) string {
+	s := fmt.Sprintf("%v", val)
+	if len(s) > 40 {
+		s = s[:40] + "..."
+	}
+	return strconv.Quote(s)
+}
@@ -0,0 +1,132 @@
+package runnable
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+var validatorSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"query":   map[string]any{"type": "string"},
+		"limit":   map[string]any{"type": "integer"},
+		"score":   map[string]any{"type": "number"},
+		"verbose": map[string]any{"type": "boolean"},
+		"filter": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"year": map[string]any{"type": "integer"},
+			},
+			"required": []any{"year"},
+		},
+	},
+	"required": []string{"query", "limit"},
+}
+
+func TestInputValidatorCoerces(t *testing.T) {
+	v := NewInputValidator(validatorSchema)
+	input := map[string]any{
+		"query":   "golang",
+		"limit":   "10",
+		"score":   "0.5",
+		"verbose": "true",
+		"filter":  map[string]any{"year": float64(2024)},
+		"extra":   "kept",
+	}
+
+	out, err := v.Invoke(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out["limit"] != 10 || out["score"] != 0.5 || out["verbose"] != true || out["extra"] != "kept" {
+		t.Errorf("unexpected output: %v", out)
+	}
+	if filter := out["filter"].(map[string]any); filter["year"] != 2024 {
+		t.Errorf("expected nested year coerced to int, got %v", filter["year"])
+	}
+	if input["limit"] != "10" {
+		t.Error("expected input map to be left unmodified")
+	}
+}
+
+func TestInputValidatorErrors(t *testing.T) {
+	v := NewInputValidator(validatorSchema)
+	_, err := v.Invoke(context.Background(), map[string]any{
+		"limit":  "ten",
+		"score":  1.5,
+		"filter": map[string]any{},
+	})
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	for _, want := range []string{
+		`missing required key "query"`,
+		`key "limit": expected integer, got string "ten"`,
+		`missing required key "filter.year"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to contain %q, got %q", want, err)
+		}
+	}
+}
+
+func TestInputValidatorNumericKinds(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"n": map[string]any{"type": "integer"},
+			"x": map[string]any{"type": "number"},
+		},
+	}
+	v := NewInputValidator(schema)
+
+	for _, tc := range []struct {
+		key  string
+		in   any
+		want any
+	}{
+		{"n", int8(-3), -3},
+		{"n", int32(7), 7},
+		{"n", uint16(4), 4},
+		{"n", uint64(9), 9},
+		{"n", float32(5), 5},
+		{"n", json.Number("12"), 12},
+		{"n", json.Number("3.0"), 3},
+		{"x", int8(2), 2.0},
+		{"x", uint32(6), 6.0},
+		{"x", float32(0.5), 0.5},
+	} {
+		out, err := v.Invoke(context.Background(), map[string]any{tc.key: tc.in})
+		if err != nil {
+			t.Errorf("%T %v: unexpected error: %v", tc.in, tc.in, err)
+			continue
+		}
+		if out[tc.key] != tc.want {
+			t.Errorf("%T %v: expected %v, got %T %v", tc.in, tc.in, tc.want, out[tc.key], out[tc.key])
+		}
+	}
+
+	for _, in := range []any{2.5, 1e20, math.Inf(1), math.NaN(), uint64(math.MaxUint64), json.Number("1e30")} {
+		if _, err := v.Invoke(context.Background(), map[string]any{"n": in}); err == nil {
+			t.Errorf("%T %v: expected an out-of-range or fractional value to be rejected", in, in)
+		}
+	}
+}
+
+func TestInputValidatorInSequence(t *testing.T) {
+	double := NewLambda(func(_ context.Context, in map[string]any) (int, error) {
+		return in["limit"].(int) * 2, nil
+	})
+	seq := Pipe2(NewInputValidator(validatorSchema), double)
+
+	got, err := seq.Invoke(context.Background(), map[string]any{"query": "q", "limit": "21"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != 42 {
+		t.Errorf("expected 42, got %d", got)
+	}
+}
@@ -111,42 +111,47 @@ func (s *RecursiveCharacterTextSplitter) splitText(text string, separators []str
 	return finalChunks
 }
 
+// mergeSplits joins splits with separator into chunks of at most ChunkSize.
+// Each new chunk starts with the trailing splits of the previous one, up to
+// ChunkOverlap in length, as long as that still leaves room for the next split.
 func (s *RecursiveCharacterTextSplitter) mergeSplits(splits []string, separator string) []string {
 	var docs []string
 	var currentDoc []string
+	sepLen := 0
+	if separator != "" {
+		sepLen = s.LengthFunction(separator)
+	}
+	// total is the length of strings.Join(currentDoc, separator).
 	total := 0
+	// lengthWith returns total after appending a split of length n.
+	lengthWith := func(n int) int {
+		if len(currentDoc) == 0 {
+			return n
+		}
+		return total + sepLen + n
+	}
 
 	for _, d := range splits {
 		dLen := s.LengthFunction(d)
-		sepLen := 0
-		if separator != "" {
-			sepLen = s.LengthFunction(separator)
-		}
 
-		if total+dLen+(sepLen*len(currentDoc)) > s.ChunkSize && len(currentDoc) > 0 {
+		if len(currentDoc) > 0 && lengthWith(dLen) > s.ChunkSize {
 			doc := strings.Join(currentDoc, separator)
 			if strings.TrimSpace(doc) != "" {
 				docs = append(docs, doc)
 			}
-			// Handle overlap: keep trailing elements.
-			for total > s.ChunkOverlap || (total+dLen+(sepLen*len(currentDoc)) > s.ChunkSize && total > 0) {
-				if len(currentDoc) == 0 {
-					break
-				}
-				removed := currentDoc[0]
-				currentDoc = currentDoc[1:]
-				total -= s.LengthFunction(removed)
-				if len(currentDoc) > 0 {
+			// Handle overlap: drop leading splits until the remainder fits in
+			// ChunkOverlap and leaves room for d.
+			for len(currentDoc) > 0 && (total > s.ChunkOverlap || lengthWith(dLen) > s.ChunkSize) {
+				total -= s.LengthFunction(currentDoc[0])
+				if len(currentDoc) > 1 {
 					total -= sepLen
 				}
+				currentDoc = currentDoc[1:]
 			}
 		}
 
+		total = lengthWith(dLen)
 		currentDoc = append(currentDoc, d)
-		total += dLen
-		if len(currentDoc) > 1 {
-			total += sepLen
-		}
 	}
 
 	doc := strings.Join(currentDoc, separator)
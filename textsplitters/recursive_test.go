@@ -1,6 +1,8 @@
 package textsplitters
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/LucaLanziani/langchain-go/core"
@@ -67,3 +69,80 @@ func TestSplitDocuments(t *testing.T) {
 		}
 	}
 }
+
+// overlapLen returns the length of the longest suffix of a that is a prefix of b.
+func overlapLen(a, b string) int {
+	for n := min(len(a), len(b)); n > 0; n-- {
+		if strings.HasSuffix(a, b[:n]) {
+			return n
+		}
+	}
+	return 0
+}
+
+func TestRecursiveCharacterTextSplitterSingleCharSplits(t *testing.T) {
+	text := "a b c d e f g h i j"
+	tests := []struct {
+		size, overlap int
+		want          []string
+	}{
+		{5, 0, []string{"a b c", "d e f", "g h i", "j"}},
+		{5, 2, []string{"a b c", "c d e", "e f g", "g h i", "i j"}},
+		// Overlap one short of the chunk size still makes progress.
+		{5, 4, []string{"a b c", "b c d", "c d e", "d e f", "e f g", "f g h", "g h i", "h i j"}},
+	}
+	for _, tt := range tests {
+		got := NewRecursiveCharacterTextSplitter(tt.size, tt.overlap).SplitText(text)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("size=%d overlap=%d: expected %q, got %q", tt.size, tt.overlap, tt.want, got)
+		}
+	}
+}
+
+func TestRecursiveCharacterTextSplitterBounds(t *testing.T) {
+	// Unique tokens make the overlap between consecutive chunks unambiguous.
+	var words []string
+	for i := 0; i < 300; i++ {
+		words = append(words, fmt.Sprintf("w%03d", i))
+		if i%17 == 0 {
+			words = append(words, "\n")
+		}
+	}
+	text := strings.Join(words, " ")
+
+	for _, tt := range []struct{ size, overlap int }{
+		{12, 0}, {12, 5}, {20, 19}, {9, 8}, {30, 10}, {64, 63},
+	} {
+		chunks := NewRecursiveCharacterTextSplitter(tt.size, tt.overlap).SplitText(text)
+		if len(chunks) == 0 {
+			t.Fatalf("size=%d overlap=%d: expected chunks", tt.size, tt.overlap)
+		}
+		for i, c := range chunks {
+			if len(c) > tt.size {
+				t.Errorf("size=%d overlap=%d: chunk %d has %d chars: %q", tt.size, tt.overlap, i, len(c), c)
+			}
+			if i > 0 {
+				if o := overlapLen(chunks[i-1], c); o > tt.overlap+1 {
+					t.Errorf("size=%d overlap=%d: chunks %d and %d overlap by %d: %q | %q",
+						tt.size, tt.overlap, i-1, i, o, chunks[i-1], c)
+				}
+			}
+		}
+	}
+}
+
+func TestRecursiveCharacterTextSplitterLongRun(t *testing.T) {
+	// No separators at all: the text is split character by character.
+	text := strings.Repeat("x", 1000)
+	chunks := NewRecursiveCharacterTextSplitter(7, 3).SplitText(text)
+
+	// Each chunk advances by ChunkSize-ChunkOverlap characters.
+	if len(chunks) != 250 {
+		t.Errorf("expected 250 chunks, got %d", len(chunks))
+	}
+	for i, c := range chunks {
+		if len(c) > 7 || (i < len(chunks)-1 && len(c) != 7) {
+			t.Errorf("chunk %d has unexpected length %d", i, len(c))
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package textsplitters
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/LucaLanziani/langchain-go/core"
+	"github.com/LucaLanziani/langchain-go/embeddings"
+)
+
+// SemanticSplitter splits text into topically coherent chunks. It embeds
+// each sentence and cuts wherever the embedding distance between two
+// consecutive sentences is above a percentile-based threshold.
+type SemanticSplitter struct {
+	// Embedder computes the sentence embeddings.
+	Embedder embeddings.Embedder
+
+	// BreakpointPercentile selects the distance threshold: a chunk boundary is
+	// placed between sentences whose distance exceeds this percentile (0-100)
+	// of all adjacent distances. Default: 95.
+	BreakpointPercentile float64
+
+	// MinChunkSize is the minimum chunk length. Breakpoints that would produce
+	// a shorter chunk are ignored. 0 means no minimum.
+	MinChunkSize int
+
+	// MaxChunkSize is the maximum chunk length. Chunks are cut before they
+	// would exceed it, regardless of breakpoints. A single sentence longer
+	// than MaxChunkSize is split further with a RecursiveCharacterTextSplitter.
+	// 0 means no maximum.
+	MaxChunkSize int
+
+	// BatchSize is the number of sentences embedded per call. Default: 32.
+	BatchSize int
+
+	// LengthFunction computes the length of a string. Defaults to len().
+	LengthFunction func(string) int
+}
+
+// SemanticSplitterOption configures a SemanticSplitter.
+type SemanticSplitterOption func(*SemanticSplitter)
+
+// WithBreakpointPercentile sets the percentile used to pick breakpoints.
+func WithBreakpointPercentile(p float64) SemanticSplitterOption {
+	return func(s *SemanticSplitter) { s.BreakpointPercentile = p }
+}
+
+// WithMinChunkSize sets the minimum chunk length.
+func WithMinChunkSize(n int) SemanticSplitterOption {
+	return func(s *SemanticSplitter) { s.MinChunkSize = n }
+}
+
+// WithMaxChunkSize sets the maximum chunk length.
+func WithMaxChunkSize(n int) SemanticSplitterOption {
+	return func(s *SemanticSplitter) { s.MaxChunkSize = n }
+}
+
+// WithEmbeddingBatchSize sets how many sentences are embedded per call.
+func WithEmbeddingBatchSize(n int) SemanticSplitterOption {
+	return func(s *SemanticSplitter) { s.BatchSize = n }
+}
+
+// NewSemanticSplitter creates a SemanticSplitter using the given embedder.
+func NewSemanticSplitter(embedder embeddings.Embedder, opts ...SemanticSplitterOption) *SemanticSplitter {
+	s := &SemanticSplitter{
+		Embedder:             embedder,
+		BreakpointPercentile: 95,
+		BatchSize:            32,
+		LengthFunction: func(s string) int {
+			return len(s)
+		},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// SplitText splits a text string into semantically coherent chunks.
+func (s *SemanticSplitter) SplitText(ctx context.Context, text string) ([]string, error) {
+	sentences := splitSentences(text)
+	if len(sentences) == 1 && s.MaxChunkSize > 0 && s.LengthFunction(sentences[0]) > s.MaxChunkSize {
+		return s.splitOversized(sentences[0]), nil
+	}
+	if len(sentences) <= 1 {
+		return sentences, nil
+	}
+
+	vecs, err := s.embedSentences(ctx, sentences)
+	if err != nil {
+		return nil, err
+	}
+
+	distances := make([]float64, len(sentences)-1)
+	for i := range distances {
+		distances[i] = 1 - embeddings.CosineSimilarity(vecs[i], vecs[i+1])
+	}
+	threshold := percentile(distances, s.BreakpointPercentile)
+
+	return s.buildChunks(sentences, distances, threshold), nil
+}
+
+// SplitDocuments splits multiple documents into semantically coherent documents.
+func (s *SemanticSplitter) SplitDocuments(ctx context.Context, documents []*core.Document) ([]*core.Document, error) {
+	var result []*core.Document
+	for i, doc := range documents {
+		chunks, err := s.SplitText(ctx, doc.PageContent)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", i, err)
+		}
+		for _, chunk := range chunks {
+			result = append(result, &core.Document{
+				PageContent: chunk,
+				Metadata:    copyMetadata(doc.Metadata),
+			})
+		}
+	}
+	return result, nil
+}
+
+// embedSentences embeds sentences in batches of BatchSize.
+func (s *SemanticSplitter) embedSentences(ctx context.Context, sentences []string) ([][]float64, error) {
+	batchSize := s.BatchSize
+	if batchSize <= 0 {
+		batchSize = len(sentences)
+	}
+	vecs := make([][]float64, 0, len(sentences))
+	for start := 0; start < len(sentences); start += batchSize {
+		end := start + batchSize
+		if end > len(sentences) {
+			end = len(sentences)
+		}
+		batch, err := s.Embedder.EmbedDocuments(ctx, sentences[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("failed to embed sentences: %w", err)
+		}
+		if len(batch) != end-start {
+			return nil, fmt.Errorf("embedder returned %d vectors for %d sentences", len(batch), end-start)
+		}
+		vecs = append(vecs, batch...)
+	}
+	return vecs, nil
+}
+
+// buildChunks groups sentences into chunks, cutting at breakpoints while
+// respecting the min and max chunk sizes.
+func (s *SemanticSplitter) buildChunks(sentences []string, distances []float64, threshold float64) []string {
+	const sep = " "
+	sepLen := s.LengthFunction(sep)
+	var chunks []string
+	var current []string
+	currentLen := 0
+
+	flush := func() {
+		if len(current) > 0 {
+			chunks = append(chunks, strings.Join(current, sep))
+			current = nil
+			currentLen = 0
+		}
+	}
+
+	for i, sentence := range sentences {
+		sLen := s.LengthFunction(sentence)
+		if s.MaxChunkSize > 0 && sLen > s.MaxChunkSize {
+			flush()
+			chunks = append(chunks, s.splitOversized(sentence)...)
+			continue
+		}
+		if len(current) > 0 {
+			isBreak := distances[i-1] > threshold && currentLen >= s.MinChunkSize
+			tooLong := s.MaxChunkSize > 0 && currentLen+sepLen+sLen > s.MaxChunkSize
+			if isBreak || tooLong {
+				flush()
+			}
+		}
+		if len(current) > 0 {
+			currentLen += sepLen
+		}
+		current = append(current, sentence)
+		currentLen += sLen
+	}
+
+	// Fold an undersized trailing chunk into its predecessor when it fits.
+	if len(chunks) > 0 && len(current) > 0 && currentLen < s.MinChunkSize {
+		last := chunks[len(chunks)-1]
+		merged := last + sep + strings.Join(current, sep)
+		if s.MaxChunkSize <= 0 || s.LengthFunction(merged) <= s.MaxChunkSize {
+			chunks[len(chunks)-1] = merged
+			current = nil
+		}
+	}
+	flush()
+
+	return chunks
+}
+
+// splitOversized splits a sentence longer than MaxChunkSize into pieces that
+// fit, breaking on whitespace where possible.
+func (s *SemanticSplitter) splitOversized(sentence string) []string {
+	splitter := NewRecursiveCharacterTextSplitter(s.MaxChunkSize, 0)
+	splitter.LengthFunction = s.LengthFunction
+	return splitter.SplitText(sentence)
+}
+
+// splitSentences splits text after sentence-ending punctuation followed by whitespace.
+func splitSentences(text string) []string {
+	var sentences []string
+	runes := []rune(text)
+	start := 0
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case '.', '!', '?':
+			if i+1 < len(runes) && unicode.IsSpace(runes[i+1]) {
+				if sentence := strings.TrimSpace(string(runes[start : i+1])); sentence != "" {
+					sentences = append(sentences, sentence)
+				}
+				start = i + 1
+			}
+		}
+	}
+	if sentence := strings.TrimSpace(string(runes[start:])); sentence != "" {
+		sentences = append(sentences, sentence)
+	}
+	return sentences
+}
+
+// percentile returns the p-th percentile (0-100) of values using linear interpolation.
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	if p <= 0 {
+		return sorted[0]
+	}
+	if p >= 100 {
+		return sorted[len(sorted)-1]
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	frac := rank - float64(lower)
+	return sorted[lower] + (sorted[upper]-sorted[lower])*frac
+}
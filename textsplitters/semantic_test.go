@@ -0,0 +1,170 @@
+package textsplitters
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/LucaLanziani/langchain-go/core"
+)
+
+// topicEmbedder embeds sentences by topic keyword so that sentences about the
+// same topic are identical vectors and different topics are orthogonal.
+type topicEmbedder struct {
+	calls int
+}
+
+func (e *topicEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float64, error) {
+	e.calls++
+	vecs := make([][]float64, len(texts))
+	for i, t := range texts {
+		switch {
+		case strings.Contains(t, "cat"):
+			vecs[i] = []float64{1, 0, 0}
+		case strings.Contains(t, "rocket"):
+			vecs[i] = []float64{0, 1, 0}
+		default:
+			vecs[i] = []float64{0, 0, 1}
+		}
+	}
+	return vecs, nil
+}
+
+func (e *topicEmbedder) EmbedQuery(ctx context.Context, text string) ([]float64, error) {
+	vecs, err := e.EmbedDocuments(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vecs[0], nil
+}
+
+const topicText = "The cat sleeps. The cat purrs. A cat likes fish. " +
+	"The rocket launches. A rocket needs fuel. The rocket lands."
+
+func TestSemanticSplitter(t *testing.T) {
+	splitter := NewSemanticSplitter(&topicEmbedder{}, WithBreakpointPercentile(50))
+
+	chunks, err := splitter.SplitText(context.Background(), topicText)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(chunks) != 2 {
+		t.Fatalf("expected 2 chunks, got %d: %q", len(chunks), chunks)
+	}
+	if chunks[0] != "The cat sleeps. The cat purrs. A cat likes fish." {
+		t.Errorf("unexpected first chunk: %q", chunks[0])
+	}
+	if chunks[1] != "The rocket launches. A rocket needs fuel. The rocket lands." {
+		t.Errorf("unexpected second chunk: %q", chunks[1])
+	}
+}
+
+func TestSemanticSplitterBatching(t *testing.T) {
+	embedder := &topicEmbedder{}
+	splitter := NewSemanticSplitter(embedder, WithEmbeddingBatchSize(2))
+
+	if _, err := splitter.SplitText(context.Background(), topicText); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if embedder.calls != 3 {
+		t.Errorf("expected 3 embedding calls for 6 sentences, got %d", embedder.calls)
+	}
+}
+
+func TestSemanticSplitterMaxChunkSize(t *testing.T) {
+	splitter := NewSemanticSplitter(&topicEmbedder{}, WithMaxChunkSize(35))
+
+	chunks, err := splitter.SplitText(context.Background(), topicText)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, chunk := range chunks {
+		if len(chunk) > 35 {
+			t.Errorf("chunk %d exceeds max size: %d chars: %q", i, len(chunk), chunk)
+		}
+	}
+}
+
+func TestSemanticSplitterMinChunkSize(t *testing.T) {
+	splitter := NewSemanticSplitter(&topicEmbedder{}, WithBreakpointPercentile(50), WithMinChunkSize(1000))
+
+	chunks, err := splitter.SplitText(context.Background(), topicText)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(chunks) != 1 {
+		t.Errorf("expected breakpoints to be ignored below min size, got %d chunks", len(chunks))
+	}
+}
+
+func TestSemanticSplitterDocuments(t *testing.T) {
+	splitter := NewSemanticSplitter(&topicEmbedder{}, WithBreakpointPercentile(50))
+
+	docs := []*core.Document{
+		{PageContent: topicText, Metadata: map[string]any{"source": "test"}},
+	}
+	result, err := splitter.SplitDocuments(context.Background(), docs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("expected 2 documents, got %d", len(result))
+	}
+	for _, doc := range result {
+		if doc.Metadata["source"] != "test" {
+			t.Error("metadata not preserved")
+		}
+	}
+}
+
+func TestSemanticSplitterLengthFunction(t *testing.T) {
+	splitter := NewSemanticSplitter(&topicEmbedder{}, WithMaxChunkSize(6))
+	splitter.LengthFunction = func(s string) int { return len(strings.Fields(s)) }
+
+	chunks, err := splitter.SplitText(context.Background(), "The cat sleeps. The cat purrs. A cat likes fish.")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The separator counts as no words, so two three-word sentences fit.
+	if len(chunks) != 2 || chunks[0] != "The cat sleeps. The cat purrs." {
+		t.Errorf("expected chunks measured in words, got %q", chunks)
+	}
+}
+
+func TestSemanticSplitterOversizedSentence(t *testing.T) {
+	splitter := NewSemanticSplitter(&topicEmbedder{}, WithMaxChunkSize(20))
+
+	chunks, err := splitter.SplitText(context.Background(), "The cat sleeps. The rocket launches from the pad at dawn. The cat purrs.")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(chunks) < 4 || chunks[0] != "The cat sleeps." || chunks[len(chunks)-1] != "The cat purrs." {
+		t.Fatalf("expected the long sentence to be split between the short ones, got %q", chunks)
+	}
+	for i, chunk := range chunks {
+		if len(chunk) > 20 {
+			t.Errorf("chunk %d exceeds max size: %d chars: %q", i, len(chunk), chunk)
+		}
+	}
+}
+
+func TestSemanticSplitterOversizedSingleSentence(t *testing.T) {
+	embedder := &topicEmbedder{}
+	splitter := NewSemanticSplitter(embedder, WithMaxChunkSize(10))
+
+	chunks, err := splitter.SplitText(context.Background(), strings.Repeat("word ", 20))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(chunks) < 2 {
+		t.Fatalf("expected unpunctuated text to be split, got %q", chunks)
+	}
+	for i, chunk := range chunks {
+		if len(chunk) > 10 {
+			t.Errorf("chunk %d exceeds max size: %d chars: %q", i, len(chunk), chunk)
+		}
+	}
+	if embedder.calls != 0 {
+		t.Errorf("expected no embedding calls for a single sentence, got %d", embedder.calls)
+	}
+}
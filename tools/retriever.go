@@ -0,0 +1,131 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/LucaLanziani/langchain-go/core"
+	"github.com/LucaLanziani/langchain-go/retrievers"
+)
+
+// RetrieverTool wraps a Retriever as a Tool so that an agent can decide when
+// to search a knowledge base. The tool input is the query and the output is
+// the retrieved documents, each labelled with its source.
+type RetrieverTool struct {
+	retriever   retrievers.Retriever
+	name        string
+	description string
+	maxDocs     int
+	format      func(doc *core.Document) string
+	separator   string
+}
+
+// RetrieverToolOption configures a RetrieverTool.
+type RetrieverToolOption func(*RetrieverTool)
+
+// WithMaxDocs limits the number of documents included in the tool output.
+// Zero or a negative value means no limit.
+func WithMaxDocs(n int) RetrieverToolOption {
+	return func(t *RetrieverTool) {
+		t.maxDocs = n
+	}
+}
+
+// WithDocumentFormatter sets the function used to render each document.
+// The default renders the "source" metadata, if present, followed by the
+// page content.
+func WithDocumentFormatter(fn func(doc *core.Document) string) RetrieverToolOption {
+	return func(t *RetrieverTool) {
+		t.format = fn
+	}
+}
+
+// WithDocumentSeparator sets the string placed between formatted documents.
+// Default is a blank line.
+func WithDocumentSeparator(sep string) RetrieverToolOption {
+	return func(t *RetrieverTool) {
+		t.separator = sep
+	}
+}
+
+// NewRetrieverTool creates a Tool that queries the given retriever.
+func NewRetrieverTool(retriever retrievers.Retriever, name, description string, opts ...RetrieverToolOption) *RetrieverTool {
+	t := &RetrieverTool{
+		retriever:   retriever,
+		name:        name,
+		description: description,
+		format:      formatDocumentWithSource,
+		separator:   "\n\n",
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Name returns the tool name.
+func (t *RetrieverTool) Name() string { return t.name }
+
+// Description returns the tool description.
+func (t *RetrieverTool) Description() string { return t.description }
+
+// ArgsSchema returns the JSON Schema for the tool's parameters.
+func (t *RetrieverTool) ArgsSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"query": map[string]any{
+				"type":        "string",
+				"description": "The query to look up",
+			},
+		},
+		"required": []string{"query"},
+	}
+}
+
+// Run retrieves documents for the query and returns them formatted as text.
+// The input may be a bare query string or a JSON object with a "query" field.
+func (t *RetrieverTool) Run(ctx context.Context, input string) (string, error) {
+	query := input
+	var args struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal([]byte(input), &args); err == nil && args.Query != "" {
+		query = args.Query
+	}
+
+	var opts []retrievers.Option
+	if t.maxDocs > 0 {
+		opts = append(opts, retrievers.WithK(t.maxDocs))
+	}
+	docs, err := t.retriever.GetRelevantDocuments(ctx, query, opts...)
+	if err != nil {
+		return "", fmt.Errorf("retriever tool %s: %w", t.name, err)
+	}
+	if len(docs) == 0 {
+		return "No relevant documents found.", nil
+	}
+	if t.maxDocs > 0 && len(docs) > t.maxDocs {
+		docs = docs[:t.maxDocs]
+	}
+
+	parts := make([]string, len(docs))
+	for i, doc := range docs {
+		parts[i] = t.format(doc)
+	}
+	return strings.Join(parts, t.separator), nil
+}
+
+// formatDocumentWithSource renders a document as its source line, if the
+// document has one, followed by its content.
+func formatDocumentWithSource(doc *core.Document) string {
+	if source, ok := doc.Metadata["source"]; ok {
+		return fmt.Sprintf("Source: %v\n%s", source, doc.PageContent)
+	}
+	return doc.PageContent
+}
+
+// Ensure RetrieverTool implements Tool.
+var _ Tool = (*RetrieverTool)(nil)
@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/LucaLanziani/langchain-go/core"
+	"github.com/LucaLanziani/langchain-go/retrievers"
+)
+
+// fakeRetriever returns a fixed set of documents and records the last query.
+type fakeRetriever struct {
+	docs  []*core.Document
+	query string
+}
+
+func (r *fakeRetriever) GetName() string { return "fakeRetriever" }
+func (r *fakeRetriever) GetRelevantDocuments(_ context.Context, query string, _ ...retrievers.Option) ([]*core.Document, error) {
+	r.query = query
+	return r.docs, nil
+}
+func (r *fakeRetriever) Invoke(ctx context.Context, input string, opts ...core.Option) ([]*core.Document, error) {
+	return r.GetRelevantDocuments(ctx, input)
+}
+func (r *fakeRetriever) Stream(ctx context.Context, input string, opts ...core.Option) (*core.StreamIterator[[]*core.Document], error) {
+	docs, _ := r.GetRelevantDocuments(ctx, input)
+	ch := make(chan core.StreamChunk[[]*core.Document], 1)
+	ch <- core.StreamChunk[[]*core.Document]{Value: docs}
+	close(ch)
+	return core.NewStreamIterator(ch), nil
+}
+func (r *fakeRetriever) Batch(ctx context.Context, inputs []string, opts ...core.Option) ([][]*core.Document, error) {
+	results := make([][]*core.Document, len(inputs))
+	for i, in := range inputs {
+		results[i], _ = r.GetRelevantDocuments(ctx, in)
+	}
+	return results, nil
+}
+
+func TestRetrieverTool(t *testing.T) {
+	retriever := &fakeRetriever{docs: []*core.Document{
+		{PageContent: "Go was released in 2009.", Metadata: map[string]any{"source": "go.md"}},
+		{PageContent: "Rust 1.0 shipped in 2015."},
+		{PageContent: "Zig is younger.", Metadata: map[string]any{"source": "zig.md"}},
+	}}
+	tool := NewRetrieverTool(retriever, "search_docs", "Search the docs", WithMaxDocs(2))
+
+	if tool.Name() != "search_docs" || tool.Description() != "Search the docs" {
+		t.Errorf("unexpected name/description: %q %q", tool.Name(), tool.Description())
+	}
+
+	out, err := tool.Run(context.Background(), `{"query":"when was go released"}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if retriever.query != "when was go released" {
+		t.Errorf("expected query from JSON input, got %q", retriever.query)
+	}
+	want := "Source: go.md\nGo was released in 2009.\n\nRust 1.0 shipped in 2015."
+	if out != want {
+		t.Errorf("expected %q, got %q", want, out)
+	}
+
+	// Plain string input is used as the query as-is.
+	if _, err := tool.Run(context.Background(), "zig"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if retriever.query != "zig" {
+		t.Errorf("expected raw query, got %q", retriever.query)
+	}
+}
+
+func TestRetrieverToolFormatting(t *testing.T) {
+	retriever := &fakeRetriever{docs: []*core.Document{
+		{PageContent: "a"}, {PageContent: "b"},
+	}}
+	tool := NewRetrieverTool(retriever, "search", "Search",
+		WithDocumentFormatter(func(doc *core.Document) string { return "<" + doc.PageContent + ">" }),
+		WithDocumentSeparator("|"),
+	)
+	out, err := tool.Run(context.Background(), "q")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "<a>|<b>" {
+		t.Errorf("expected '<a>|<b>', got %q", out)
+	}
+
+	retriever.docs = nil
+	out, _ = tool.Run(context.Background(), "q")
+	if !strings.Contains(out, "No relevant documents") {
+		t.Errorf("expected empty-result message, got %q", out)
+	}
+}
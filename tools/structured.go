@@ -80,6 +80,101 @@ func NewTypedTool[T any](name, description string, argsExample T, fn func(ctx co
 	}
 }
 
+// TypedTool is a tool with typed arguments and a typed result. The result is
+// marshaled to JSON for the model and returned as the artifact for code; see
+// ArtifactTool.
+type TypedTool[Args, Result any] struct {
+	name        string
+	description string
+	argsSchema  map[string]any
+	fn          func(ctx context.Context, args Args) (Result, error)
+}
+
+// NewTypedToolWithResult creates a TypedTool. The argument schema is generated
+// from Args with the same rules as NewTypedTool. A string Result is passed to
+// the model as is; any other Result is marshaled to JSON.
+//
+// Example:
+//
+//	type WeatherArgs struct {
+//	    City string `json:"city" description:"The city name"`
+//	}
+//	type Weather struct {
+//	    TempC float64 `json:"temp_c"`
+//	}
+//	tool := NewTypedToolWithResult("weather", "Get the weather",
+//	    func(ctx context.Context, args WeatherArgs) (Weather, error) {
+//	        return Weather{TempC: 21}, nil
+//	    },
+//	)
+func NewTypedToolWithResult[Args, Result any](name, description string, fn func(ctx context.Context, args Args) (Result, error)) *TypedTool[Args, Result] {
+	var zero Args
+	return &TypedTool[Args, Result]{
+		name:        name,
+		description: description,
+		argsSchema:  generateJSONSchema(zero),
+		fn:          fn,
+	}
+}
+
+// Name returns the tool name.
+func (t *TypedTool[Args, Result]) Name() string { return t.name }
+
+// Description returns the tool description.
+func (t *TypedTool[Args, Result]) Description() string { return t.description }
+
+// ArgsSchema returns the JSON Schema for the tool's parameters.
+func (t *TypedTool[Args, Result]) ArgsSchema() map[string]any { return t.argsSchema }
+
+// Call runs the tool function with already-parsed arguments.
+func (t *TypedTool[Args, Result]) Call(ctx context.Context, args Args) (Result, error) {
+	return t.fn(ctx, args)
+}
+
+// Run executes the tool and returns the result serialized for the model.
+func (t *TypedTool[Args, Result]) Run(ctx context.Context, input string) (string, error) {
+	output, _, err := t.RunWithArtifact(ctx, input)
+	return output, err
+}
+
+// RunWithArtifact executes the tool, returning the serialized result and the
+// typed Result as the artifact.
+func (t *TypedTool[Args, Result]) RunWithArtifact(ctx context.Context, input string) (string, any, error) {
+	var args Args
+	if err := json.Unmarshal([]byte(input), &args); err != nil {
+		return "", nil, fmt.Errorf("failed to parse tool input: %w", err)
+	}
+	result, err := t.fn(ctx, args)
+	if err != nil {
+		return "", nil, err
+	}
+	if s, ok := any(result).(string); ok {
+		return s, result, nil
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal tool result: %w", err)
+	}
+	return string(data), result, nil
+}
+
+// GenerateJSONSchema returns a JSON Schema describing values of v's type.
+// Structs are described with the same rules NewTypedTool uses for its
+// arguments; scalar types map to their JSON type.
+func GenerateJSONSchema(v any) map[string]any {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return map[string]any{}
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct {
+		return generateJSONSchema(v)
+	}
+	return map[string]any{"type": goTypeToJSONType(t.Kind())}
+}
+
 // generateJSONSchema generates a JSON Schema from a Go struct.
 func generateJSONSchema(v any) map[string]any {
 	t := reflect.TypeOf(v)
@@ -182,3 +277,6 @@ func goTypeToJSONType(k reflect.Kind) string {
 
 // Ensure StructuredTool implements Tool.
 var _ Tool = (*StructuredTool)(nil)
+
+// Ensure TypedTool implements ArtifactTool.
+var _ ArtifactTool = (*TypedTool[struct{}, any])(nil)
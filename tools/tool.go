@@ -28,6 +28,28 @@ type Tool interface {
 	Run(ctx context.Context, input string) (string, error)
 }
 
+// ArtifactTool is implemented by tools that return structured output
+// alongside the string shown to the model. ExecuteToolCalls stores the
+// artifact in ToolMessage.Artifact and the AgentExecutor in
+// AgentStep.Artifact.
+type ArtifactTool interface {
+	Tool
+
+	// RunWithArtifact executes the tool, returning the content for the model
+	// and an artifact for application code.
+	RunWithArtifact(ctx context.Context, input string) (string, any, error)
+}
+
+// RunTool executes t, returning its artifact if it is an ArtifactTool and
+// nil otherwise.
+func RunTool(ctx context.Context, t Tool, input string) (string, any, error) {
+	if at, ok := t.(ArtifactTool); ok {
+		return at.RunWithArtifact(ctx, input)
+	}
+	output, err := t.Run(ctx, input)
+	return output, nil, err
+}
+
 // ToDefinition converts a Tool to an llms.ToolDefinition for model binding.
 func ToDefinition(t Tool) llms.ToolDefinition {
 	return llms.ToolDefinition{
@@ -80,6 +102,9 @@ func (r *RunnableTool) Stream(ctx context.Context, input string, opts ...core.Op
 func (r *RunnableTool) Batch(ctx context.Context, inputs []string, opts ...core.Option) ([]string, error) {
 	results := make([]string, len(inputs))
 	for i, input := range inputs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		result, err := r.tool.Run(ctx, input)
 		if err != nil {
 			return nil, fmt.Errorf("batch item %d: %w", i, err)
@@ -91,24 +116,34 @@ func (r *RunnableTool) Batch(ctx context.Context, inputs []string, opts ...core.
 
 // ExecuteToolCall executes a tool call from an AI message, looking up the tool by name.
 func ExecuteToolCall(ctx context.Context, toolCall core.ToolCall, availableTools []Tool) (string, error) {
+	output, _, err := executeToolCall(ctx, toolCall, availableTools)
+	return output, err
+}
+
+// executeToolCall looks up and runs the tool for toolCall, returning its
+// artifact if it has one.
+func executeToolCall(ctx context.Context, toolCall core.ToolCall, availableTools []Tool) (string, any, error) {
 	for _, t := range availableTools {
 		if t.Name() == toolCall.Name {
-			return t.Run(ctx, string(toolCall.Args))
+			return RunTool(ctx, t, string(toolCall.Args))
 		}
 	}
-	return "", fmt.Errorf("tool %q not found", toolCall.Name)
+	return "", nil, fmt.Errorf("tool %q not found", toolCall.Name)
 }
 
-// ExecuteToolCalls executes all tool calls from an AI message.
+// ExecuteToolCalls executes all tool calls from an AI message. Artifacts of
+// ArtifactTools are stored in the returned ToolMessages.
 func ExecuteToolCalls(ctx context.Context, toolCalls []core.ToolCall, availableTools []Tool) ([]core.Message, error) {
 	var results []core.Message
 	for _, tc := range toolCalls {
-		output, err := ExecuteToolCall(ctx, tc, availableTools)
+		output, artifact, err := executeToolCall(ctx, tc, availableTools)
 		if err != nil {
 			// Return error as a tool message so the agent can see it.
 			output = fmt.Sprintf("Error: %v", err)
 		}
-		results = append(results, core.NewToolMessage(output, tc.ID))
+		msg := core.NewToolMessage(output, tc.ID)
+		msg.Artifact = artifact
+		results = append(results, msg)
 	}
 	return results, nil
 }
@@ -2,6 +2,7 @@ package tools
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/LucaLanziani/langchain-go/core"
@@ -74,6 +75,56 @@ func TestNewTypedTool(t *testing.T) {
 	}
 }
 
+type searchResult struct {
+	Titles []string `json:"titles"`
+	Total  int      `json:"total"`
+}
+
+func TestNewTypedToolWithResult(t *testing.T) {
+	tool := NewTypedToolWithResult("search", "Search the web",
+		func(_ context.Context, args searchArgs) (searchResult, error) {
+			return searchResult{Titles: []string{"Go: " + args.Query}, Total: 1}, nil
+		},
+	)
+
+	props, _ := tool.ArgsSchema()["properties"].(map[string]any)
+	if _, ok := props["query"]; !ok {
+		t.Fatalf("expected schema generated from args, got %v", tool.ArgsSchema())
+	}
+
+	// The model sees JSON; the typed result is the artifact.
+	calls := []core.ToolCall{{ID: "call_1", Name: "search", Args: json.RawMessage(`{"query":"generics"}`)}}
+	msgs, err := ExecuteToolCalls(context.Background(), calls, []Tool{tool})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	msg := msgs[0].(*core.ToolMessage)
+	if msg.Content != `{"titles":["Go: generics"],"total":1}` {
+		t.Errorf("unexpected content: %q", msg.Content)
+	}
+	result, ok := msg.Artifact.(searchResult)
+	if !ok || result.Total != 1 || result.Titles[0] != "Go: generics" {
+		t.Errorf("expected typed artifact, got %#v", msg.Artifact)
+	}
+
+	if _, err := tool.Run(context.Background(), "not json"); err == nil {
+		t.Error("expected error for invalid input")
+	}
+}
+
+func TestNewTypedToolWithResultString(t *testing.T) {
+	tool := NewTypedToolWithResult("echo", "Echo the query",
+		func(_ context.Context, args searchArgs) (string, error) { return args.Query, nil },
+	)
+	output, artifact, err := RunTool(context.Background(), tool, `{"query":"hi"}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output != "hi" || artifact != "hi" {
+		t.Errorf("expected unquoted string output, got %q (artifact %v)", output, artifact)
+	}
+}
+
 func TestToDefinitions(t *testing.T) {
 	tool := NewTool("test", "A test tool", func(_ context.Context, input string) (string, error) {
 		return input, nil
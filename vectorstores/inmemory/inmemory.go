@@ -4,7 +4,6 @@ package inmemory
 import (
 	"context"
 	"fmt"
-	"math"
 	"sort"
 	"sync"
 
@@ -80,8 +79,28 @@ func (s *Store) SimilaritySearch(ctx context.Context, query string, k int) ([]*c
 	return docs, nil
 }
 
+// SimilaritySearchWithFilter finds the k most similar documents whose
+// metadata matches filter.
+func (s *Store) SimilaritySearchWithFilter(ctx context.Context, query string, k int, filter map[string]any) ([]*core.Document, error) {
+	results, err := s.search(ctx, query, k, filter)
+	if err != nil {
+		return nil, err
+	}
+	docs := make([]*core.Document, len(results))
+	for i, r := range results {
+		docs[i] = r.Document
+	}
+	return docs, nil
+}
+
 // SimilaritySearchWithScore finds the k most similar documents with scores.
 func (s *Store) SimilaritySearchWithScore(ctx context.Context, query string, k int) ([]vectorstores.DocumentWithScore, error) {
+	return s.search(ctx, query, k, nil)
+}
+
+// search scores the documents matching filter against the query and
+// returns the top k.
+func (s *Store) search(ctx context.Context, query string, k int, filter map[string]any) ([]vectorstores.DocumentWithScore, error) {
 	queryVec, err := s.embedder.EmbedQuery(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to embed query: %w", err)
@@ -96,7 +115,10 @@ func (s *Store) SimilaritySearchWithScore(ctx context.Context, query string, k i
 	}
 	var scored_ []scored
 	for _, d := range s.docs {
-		sim := cosineSimilarity(queryVec, d.Embedding)
+		if !vectorstores.MatchesFilter(d.Document.Metadata, filter) {
+			continue
+		}
+		sim := embeddings.CosineSimilarity(queryVec, d.Embedding)
 		scored_ = append(scored_, scored{doc: d.Document, score: sim})
 	}
 
@@ -144,22 +166,8 @@ func (s *Store) GetEmbedder() embeddings.Embedder {
 	return s.embedder
 }
 
-// cosineSimilarity computes the cosine similarity between two vectors.
-func cosineSimilarity(a, b []float64) float64 {
-	if len(a) != len(b) {
-		return 0
-	}
-	var dotProduct, normA, normB float64
-	for i := range a {
-		dotProduct += a[i] * b[i]
-		normA += a[i] * a[i]
-		normB += b[i] * b[i]
-	}
-	if normA == 0 || normB == 0 {
-		return 0
-	}
-	return dotProduct / (math.Sqrt(normA) * math.Sqrt(normB))
-}
-
 // Ensure Store implements vectorstores.VectorStore.
 var _ vectorstores.VectorStore = (*Store)(nil)
+
+// Ensure Store implements vectorstores.FilteredSearcher.
+var _ vectorstores.FilteredSearcher = (*Store)(nil)
@@ -3,6 +3,7 @@ package vectorstores
 
 import (
 	"context"
+	"reflect"
 
 	"github.com/LucaLanziani/langchain-go/core"
 	"github.com/LucaLanziani/langchain-go/embeddings"
@@ -27,8 +28,27 @@ type VectorStore interface {
 	GetEmbedder() embeddings.Embedder
 }
 
+// FilteredSearcher is implemented by vector stores that can restrict a
+// similarity search to documents whose metadata has each key in filter set
+// to the given value.
+type FilteredSearcher interface {
+	SimilaritySearchWithFilter(ctx context.Context, query string, k int, filter map[string]any) ([]*core.Document, error)
+}
+
 // DocumentWithScore pairs a document with its similarity score.
 type DocumentWithScore struct {
 	Document *core.Document
 	Score    float64
 }
+
+// MatchesFilter reports whether metadata contains every key/value pair in
+// filter. An empty filter matches everything.
+func MatchesFilter(metadata, filter map[string]any) bool {
+	for k, want := range filter {
+		got, ok := metadata[k]
+		if !ok || !reflect.DeepEqual(got, want) {
+			return false
+		}
+	}
+	return true
+}